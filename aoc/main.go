@@ -0,0 +1,269 @@
+// Command aoc runs any day's solution, checks every day against the accepted
+// answers and gives access to the tools some days come with.
+//
+// Usage:
+//
+//	aoc run -day 3 -part 2 -input day3/input.txt
+//	aoc check -answers answers.json
+//	aoc list
+//	aoc input fetch -day 3 -url http://localhost:8080
+//	aoc fuel -format csv
+//	aoc intcode -debug
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cquon/aoc-2019/day1"
+	"github.com/cquon/aoc-2019/day2"
+	"github.com/cquon/aoc-2019/day3"
+	_ "github.com/cquon/aoc-2019/day4"
+	"github.com/cquon/aoc-2019/inputs"
+	"github.com/cquon/aoc-2019/solution"
+)
+
+// errFailed marks a command that already reported its own failure.
+var errFailed = errors.New("failed")
+
+// commands are the subcommands of aoc, each given the arguments after its name.
+var commands = map[string]func(args []string) error{
+	"run":     runCommand,
+	"check":   checkCommand,
+	"list":    listCommand,
+	"input":   inputCommand,
+	"fuel":    day1.Tool,
+	"intcode": day2.Tool,
+	"wires":   day3.Tool,
+}
+
+const usage = `usage: aoc <command> [flags]
+
+commands:
+  run      solve one day (-day N [-part 1|2] [-input file|-] [-json])
+  check    solve every day and compare against the accepted answers
+  list     list the days with a registered solution
+  input    manage the local input store (import, fetch, path, list)
+  fuel     fuel reports: [report] -input file -format table|csv|json, sum glob..., inverse -budget n, manifest -input file, diff old new, serve
+  intcode  Intcode tools: -debug, -conformance, -exec, -arcade, -droid
+  wires    wire tools: report -sort col -format table|csv|json, signal -rule first|last|loopfree -t tick -loops, route -to x,y -penalty n, query -k n -self -strict -costs UL=2 -top n -metric m -from x,y, draw -crop x0,y0,x1,y1 -png file
+`
+
+// readInput reads the input for day from fileName or "-" for stdin. When fileName
+// is empty it uses the input store, falling back to the day's committed input.
+// The input is validated against the day's format checks.
+func readInput(day int, fileName string) (string, error) {
+	if fileName == "" {
+		fileName = fmt.Sprintf("day%d/input.txt", day)
+		if store, err := inputs.DefaultStore(); err == nil && store.Has(inputs.Year, day) {
+			fileName = store.Path(inputs.Year, day)
+		}
+	}
+	var data []byte
+	var err error
+	if fileName == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(fileName)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), solution.Validate(day, string(data))
+}
+
+// answer is the JSON form of one solved part.
+type answer struct {
+	Day    int    `json:"day"`
+	Part   int    `json:"part"`
+	Answer string `json:"answer,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	day := flags.Int("day", 0, "day to solve")
+	part := flags.Int("part", 0, "part to solve, 1 or 2 (default both)")
+	inputFile := flags.String("input", "", "puzzle input file, - for stdin (default dayN/input.txt)")
+	jsonOutput := flags.Bool("json", false, "print answers as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	s, ok := solution.Lookup(*day)
+	if !ok {
+		return fmt.Errorf("no solution for day %d", *day)
+	}
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+	input, err := readInput(*day, *inputFile)
+	if err != nil {
+		return err
+	}
+
+	var answers []answer
+	failed := false
+	for _, p := range parts {
+		a := answer{Day: *day, Part: p}
+		got, err := solution.Solve(s, p, input)
+		if err != nil {
+			a.Error = err.Error()
+			failed = true
+		} else {
+			a.Answer = got
+		}
+		answers = append(answers, a)
+	}
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(answers); err != nil {
+			return err
+		}
+	} else {
+		for _, a := range answers {
+			if a.Error != "" {
+				fmt.Fprintf(os.Stderr, "Part %d: error: %s\n", a.Part, a.Error)
+			} else {
+				fmt.Printf("Part %d: %s\n", a.Part, a.Answer)
+			}
+		}
+	}
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+func checkCommand(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	answersFile := flags.String("answers", "answers.json", "accepted answers to check against")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	answers, err := solution.LoadAnswers(*answersFile)
+	if err != nil {
+		return err
+	}
+	passed := true
+	for _, day := range solution.Days() {
+		input, err := readInput(day, "")
+		if err != nil {
+			return err
+		}
+		results, err := solution.Verify(day, input, answers)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Day %d\n", day)
+		if !solution.Report(os.Stdout, results) {
+			passed = false
+		}
+	}
+	if !passed {
+		return errFailed
+	}
+	return nil
+}
+
+func listCommand(args []string) error {
+	for _, day := range solution.Days() {
+		fmt.Printf("Day %d\n", day)
+	}
+	return nil
+}
+
+func inputCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: aoc input import|fetch|path|list [flags]")
+	}
+	flags := flag.NewFlagSet("input "+args[0], flag.ContinueOnError)
+	day := flags.Int("day", 0, "day of the input")
+	year := flags.Int("year", inputs.Year, "year of the input")
+	fileName := flags.String("file", "", "import: file to import, - for stdin")
+	url := flags.String("url", "https://adventofcode.com", "fetch: server with the puzzle site's URL layout")
+	session := flags.String("session", os.Getenv("AOC_SESSION"), "fetch: session cookie (default $AOC_SESSION)")
+	dir := flags.String("dir", "", "input store directory (default $AOC_INPUT_DIR or the user cache directory)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	store := &inputs.Store{Dir: *dir}
+	if *dir == "" {
+		var err error
+		if store, err = inputs.DefaultStore(); err != nil {
+			return err
+		}
+	}
+
+	var input string
+	switch args[0] {
+	case "list":
+		keys, err := store.List()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			fmt.Printf("%d day %d\t%s\n", key.Year, key.Day, store.Path(key.Year, key.Day))
+		}
+		return nil
+	case "path":
+		fmt.Println(store.Path(*year, *day))
+		return nil
+	case "import":
+		if *fileName == "" {
+			return fmt.Errorf("import needs -file")
+		}
+		var data []byte
+		var err error
+		if *fileName == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(*fileName)
+		}
+		if err != nil {
+			return err
+		}
+		input = string(data)
+	case "fetch":
+		client := &inputs.Client{BaseURL: *url, Session: *session}
+		var err error
+		if input, err = client.Fetch(*year, *day); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown input command %q", args[0])
+	}
+	if *year == inputs.Year {
+		if err := solution.Validate(*day, input); err != nil {
+			return err
+		}
+	}
+	if err := store.Save(*year, *day, input); err != nil {
+		return err
+	}
+	fmt.Printf("Saved %d day %d to %s\n", *year, *day, store.Path(*year, *day))
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := command(os.Args[2:]); err != nil {
+		if err != errFailed && err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
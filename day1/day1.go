@@ -1,11 +1,13 @@
-package main
+package day1
 
 import (
-	"os"
-	"log"
-	"bufio"
+	"io"
 	"strconv"
+	"strings"
 	"fmt"
+
+	"github.com/cquon/aoc-2019/fuel"
+	"github.com/cquon/aoc-2019/solution"
 )
 
 /*
@@ -47,50 +49,41 @@ The fuel required by a module of mass 100756 and its fuel is: 33583 + 11192 + 37
 What is the sum of the fuel requirements for all of the modules on your spacecraft when also taking into account the mass of the added fuel? (Calculate the fuel requirements for each module separately, then add them all up at the end.)
  */
 
-func readModules(fileName string) []int {
-	var moduleWeights []int
-	file, err := os.Open(fileName)
+func readModules(r io.Reader) ([]int, error) {
+	return fuel.ReadMasses(r)
+}
+
+// fuelCounter is the day 1 solution.
+type fuelCounter struct{}
+
+func (fuelCounter) Part1(input string) (string, error) {
+	moduleWeights, err := readModules(strings.NewReader(input))
 	if err != nil {
-		log.Fatal(err)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		moduleWeightString := scanner.Text()
-		moduleWeight, err := strconv.Atoi(moduleWeightString)
-		if err != nil {
-			panic("Module weight not an integer")
-		}
-		moduleWeights = append(moduleWeights, moduleWeight)
+		return "", err
 	}
+	return strconv.Itoa(fuel.NewReport(moduleWeights).Fuel), nil
+}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+func (fuelCounter) Part2(input string) (string, error) {
+	moduleWeights, err := readModules(strings.NewReader(input))
+	if err != nil {
+		return "", err
 	}
-	return moduleWeights
+	return strconv.Itoa(fuel.NewReport(moduleWeights).Total), nil
 }
 
-func fuelRequired(moduleMass int) int {
-	return moduleMass / 3 - 2
+func init() {
+	solution.Register(1, fuelCounter{})
 }
 
-func fuelRequiredRecursive(moduleMass int) int {
-	if fuelRequired(moduleMass) <= 0 {
-		return 0
+// Validate checks the input is one integer mass per line.
+func (fuelCounter) Validate(input string) error {
+	moduleWeights, err := readModules(strings.NewReader(input))
+	if err != nil {
+		return err
 	}
-
-	return fuelRequired(moduleMass) + fuelRequiredRecursive(fuelRequired(moduleMass))
-}
-
-func main() {
-	fuel := 0
-	fuelRequiredPt2 := 0
-	moduleWeights := readModules("input.txt")
-	for _, weight := range(moduleWeights) {
-		fuel += fuelRequired(weight)
-		fuelRequiredPt2 += fuelRequiredRecursive(weight)
+	if len(moduleWeights) == 0 {
+		return fmt.Errorf("no module weights")
 	}
-	fmt.Printf("Part 1: Fuel needed is: %d\n", fuel)
-	fmt.Printf("Part 2: Fuel needed is: %d\n", fuelRequiredPt2)
-}
\ No newline at end of file
+	return nil
+}
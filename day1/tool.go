@@ -0,0 +1,425 @@
+package day1
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cquon/aoc-2019/fuel"
+)
+
+// toolCommands are the fuel tool's subcommands. Without one, Tool runs "report".
+var toolCommands = map[string]func(args []string) error{
+	"report":   reportCommand,
+	"sum":      sumCommand,
+	"inverse":  inverseCommand,
+	"manifest": manifestCommand,
+	"diff":     diffCommand,
+	"serve":    serveCommand,
+}
+
+// Tool runs the fuel calculator selected by command line style args.
+func Tool(args []string) error {
+	if len(args) > 0 {
+		if command, ok := toolCommands[args[0]]; ok {
+			return command(args[1:])
+		}
+	}
+	return reportCommand(args)
+}
+
+// modelFlags adds the flags choosing a fuel model to flags, and returns a
+// function building the model once they are parsed.
+func modelFlags(flags *flag.FlagSet) func() (fuel.Model, error) {
+	modelName := flags.String("model", "linear", "fuel model: linear or rocket")
+	divisor := flags.Int("divisor", fuel.Default.Divisor, "linear: divide the mass by this")
+	subtrahend := flags.Int("subtrahend", fuel.Default.Subtrahend, "linear: then subtract this")
+	roundingName := flags.String("rounding", "floor", "rounding of fractional fuel: floor or ceil")
+	isp := flags.Float64("isp", 300, "rocket: engine specific impulse in seconds")
+	deltaV := flags.Float64("delta-v", 1000, "rocket: change in velocity in m/s")
+	return func() (fuel.Model, error) {
+		rounding, err := fuel.ParseRounding(*roundingName)
+		if err != nil {
+			return nil, err
+		}
+		switch *modelName {
+		case "linear":
+			return fuel.NewLinear(*divisor, *subtrahend, rounding)
+		case "rocket":
+			return fuel.NewTsiolkovsky(*isp, *deltaV, rounding)
+		}
+		return nil, fmt.Errorf("unknown fuel model %q, want linear or rocket", *modelName)
+	}
+}
+
+func policy(skipBadLines bool) fuel.Policy {
+	if skipBadLines {
+		return fuel.Skip
+	}
+	return fuel.Strict
+}
+
+func reportCommand(args []string) error {
+	flags := flag.NewFlagSet("fuel report", flag.ContinueOnError)
+	inputFile := flags.String("input", "day1/input.txt", "module masses, one per line, - for stdin")
+	stream := flags.Bool("stream", false, "only print totals, reading any amount of input in constant memory")
+	skipBadLines := flags.Bool("skip-bad-lines", false, "report lines that aren't masses and carry on instead of stopping")
+	format := flags.String("format", "table", "report format: table, csv or json")
+	sortByTotal := flags.Bool("sort", false, "list the modules needing the most fuel first")
+	buildModel := modelFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	model, err := buildModel()
+	if err != nil {
+		return err
+	}
+
+	in := os.Stdin
+	if *inputFile != "-" {
+		file, err := os.Open(*inputFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+	reader := fuel.NewReader(in, policy(*skipBadLines))
+	defer reportSkipped(reader)
+
+	if *stream {
+		totals, err := fuel.Sum(reader, model)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Modules: %d\nMass: %s\nFuel: %s\nTotal fuel: %s\n", totals.Modules, totals.Mass, totals.Fuel, totals.Total)
+		return nil
+	}
+
+	var moduleWeights []int
+	for reader.Scan() {
+		if reader.BigMass() != nil {
+			return fmt.Errorf("line %d: mass %v is too large to break down, use -stream", reader.Line(), reader.BigMass())
+		}
+		moduleWeights = append(moduleWeights, reader.Mass())
+	}
+	if err := reader.Err(); err != nil {
+		return err
+	}
+	report, err := fuel.NewReportWith(model, moduleWeights)
+	if err != nil {
+		return err
+	}
+	if *sortByTotal {
+		report.SortByTotal()
+	}
+	return report.Write(os.Stdout, *format)
+}
+
+// reportSkipped lists the bad lines a reader skipped on stderr.
+func reportSkipped(reader *fuel.Reader) {
+	for _, lineErr := range reader.LineErrors {
+		fmt.Fprintln(os.Stderr, "skipped", lineErr)
+	}
+	if more := reader.Skipped - len(reader.LineErrors); more > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d more bad lines\n", more)
+	}
+}
+
+// fileSummary is the JSON form of one file's totals.
+type fileSummary struct {
+	File    string `json:"file"`
+	Modules int64  `json:"modules"`
+	Mass    string `json:"mass"`
+	Fuel    string `json:"fuel"`
+	Total   string `json:"total"`
+	Skipped int    `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func summarize(name string, totals *fuel.Totals, skipped int, err error) fileSummary {
+	s := fileSummary{File: name, Modules: totals.Modules, Mass: totals.Mass.String(), Fuel: totals.Fuel.String(), Total: totals.Total.String(), Skipped: skipped}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	return s
+}
+
+// sumCommand totals many files of module masses in parallel.
+func sumCommand(args []string) error {
+	flags := flag.NewFlagSet("fuel sum", flag.ContinueOnError)
+	workers := flags.Int("workers", runtime.NumCPU(), "files to read at once")
+	skipBadLines := flags.Bool("skip-bad-lines", false, "skip lines that aren't masses instead of failing the file")
+	format := flags.String("format", "table", "output format: table or json")
+	buildModel := modelFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: aoc fuel sum [flags] glob...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	model, err := buildModel()
+	if err != nil {
+		return err
+	}
+	files, err := fuel.Glob(flags.Args()...)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no input files match %q", flags.Args())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	results, grand := fuel.SumFiles(ctx, files, model, policy(*skipBadLines), *workers)
+
+	failed := 0
+	summaries := make([]fileSummary, 0, len(results)+1)
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+		summaries = append(summaries, summarize(result.File, result.Totals, result.Skipped, result.Err))
+	}
+	summaries = append(summaries, summarize("total", grand, 0, nil))
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(summaries); err != nil {
+			return err
+		}
+	case "table":
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "File\tModules\tMass\tFuel\tTotal\tSkipped\tError")
+		for _, s := range summaries {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%d\t%s\n", s.File, s.Modules, s.Mass, s.Fuel, s.Total, s.Skipped, s.Error)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q, want table or json", *format)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(files))
+	}
+	return nil
+}
+
+// inverseCommand goes from fuel to mass: the heaviest modules a fuel budget can
+// launch, or the lightest module needing a given amount of fuel.
+func inverseCommand(args []string) error {
+	flags := flag.NewFlagSet("fuel inverse", flag.ContinueOnError)
+	budget := flags.Int("budget", 0, "find the heaviest modules this much fuel can launch")
+	modules := flags.Int("modules", 1, "budget: number of modules to share the budget between")
+	fuelAmount := flags.Int("fuel", 0, "find the lightest module needing exactly this much fuel")
+	recursive := flags.Bool("recursive", false, "include the fuel for fuel, as in part 2")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	f := fuel.FuelFunc(fuel.Required)
+	if *recursive {
+		f = fuel.RequiredRecursive
+	}
+	switch {
+	case set["fuel"] == set["budget"]:
+		return fmt.Errorf("give one of -budget or -fuel")
+	case set["fuel"]:
+		mass, ok := fuel.MinMass(f, *fuelAmount)
+		if !ok {
+			return fmt.Errorf("no mass needs exactly %d fuel", *fuelAmount)
+		}
+		fmt.Printf("Lightest mass needing %d fuel: %d\n", *fuelAmount, mass)
+		return nil
+	}
+	masses, err := fuel.MaxMasses(f, *budget, *modules)
+	if err != nil {
+		return err
+	}
+	used := 0
+	for i, mass := range masses {
+		fmt.Printf("Module %d: mass %d, fuel %d\n", i+1, mass, max(f(mass), 0))
+		used += max(f(mass), 0)
+	}
+	fmt.Printf("Fuel used: %d of %d\n", used, *budget)
+	return nil
+}
+
+// readManifest reads a manifest or flat mass list from fileName, - for stdin.
+func readManifest(fileName string) (*fuel.Subsystem, error) {
+	if fileName == "-" {
+		return fuel.ReadManifest(os.Stdin)
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	manifest, err := fuel.ReadManifest(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return manifest, nil
+}
+
+// manifestCommand rolls up the fuel of a spacecraft manifest per subsystem.
+func manifestCommand(args []string) error {
+	flags := flag.NewFlagSet("fuel manifest", flag.ContinueOnError)
+	inputFile := flags.String("input", "day1/input.txt", "JSON manifest or flat list of masses, - for stdin")
+	format := flags.String("format", "tree", "output format: tree or json")
+	byTag := flags.Bool("tags", false, "total the fuel per tag instead of per subsystem")
+	buildModel := modelFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	model, err := buildModel()
+	if err != nil {
+		return err
+	}
+	manifest, err := readManifest(*inputFile)
+	if err != nil {
+		return err
+	}
+
+	var rollups []*fuel.Rollup
+	if *byTag {
+		if rollups, err = fuel.TagTotals(manifest, model); err != nil {
+			return err
+		}
+	} else {
+		rollup, err := fuel.RollUp(manifest, model)
+		if err != nil {
+			return err
+		}
+		rollups = append(rollups, rollup)
+	}
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if *byTag {
+			return encoder.Encode(rollups)
+		}
+		return encoder.Encode(rollups[0])
+	case "tree":
+		for _, rollup := range rollups {
+			if err := rollup.WriteTree(os.Stdout); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %q, want tree or json", *format)
+}
+
+// diffCommand compares the fuel of two manifests or mass lists, and can fail
+// when the fuel goes over budget so CI can gate on it.
+func diffCommand(args []string) error {
+	flags := flag.NewFlagSet("fuel diff", flag.ContinueOnError)
+	format := flags.String("format", "table", "output format: table or json")
+	all := flags.Bool("all", false, "json: include unchanged modules")
+	maxIncrease := flags.Int("max-increase", -1, "fail if the total fuel grows by more than this (-1 for no limit)")
+	budget := flags.Int("budget", -1, "fail if the new total fuel is more than this (-1 for no limit)")
+	buildModel := modelFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: aoc fuel diff [flags] old new")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("want an old and a new manifest")
+	}
+	model, err := buildModel()
+	if err != nil {
+		return err
+	}
+	oldManifest, err := readManifest(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	newManifest, err := readManifest(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	diff, err := fuel.Compare(oldManifest, newManifest, model)
+	if err != nil {
+		return err
+	}
+	diff.SortByImpact()
+
+	switch *format {
+	case "json":
+		if !*all {
+			changed := diff.Modules[:0:0]
+			for _, delta := range diff.Modules {
+				if delta.Status != "unchanged" {
+					changed = append(changed, delta)
+				}
+			}
+			diff.Modules = changed
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diff); err != nil {
+			return err
+		}
+	case "table":
+		if err := diff.WriteTable(os.Stdout); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q, want table or json", *format)
+	}
+
+	if increase := diff.Total.TotalDelta(); *maxIncrease >= 0 && increase > *maxIncrease {
+		return fmt.Errorf("total fuel grew by %d, more than the allowed %d", increase, *maxIncrease)
+	}
+	if *budget >= 0 && diff.Total.NewTotal > *budget {
+		return fmt.Errorf("total fuel %d is over the budget of %d", diff.Total.NewTotal, *budget)
+	}
+	return nil
+}
+
+// serveCommand runs the fuel HTTP service.
+func serveCommand(args []string) error {
+	flags := flag.NewFlagSet("fuel serve", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	maxBytes := flags.Int64("max-bytes", fuel.DefaultServiceOptions.MaxBytes, "largest request body")
+	maxModules := flags.Int("max-modules", fuel.DefaultServiceOptions.MaxModules, "most modules in one request")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	service := fuel.NewService(fuel.ServiceOptions{MaxBytes: *maxBytes, MaxModules: *maxModules})
+	server := &http.Server{Addr: *addr, Handler: service, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	fmt.Fprintf(os.Stderr, "serving fuel API on %s\n", *addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package day2
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+)
+
+// Tile ids drawn by arcade programs.
+const (
+	tileEmpty = iota
+	tileWall
+	tileBlock
+	tilePaddle
+	tileBall
+)
+
+// defaultPalette maps tile ids, by position, to the characters drawn in the terminal.
+const defaultPalette = " #=_o"
+
+// tileColors are the colours tiles are drawn in when saving images; unknown tiles are magenta.
+var tileColors = []color.Color{
+	color.RGBA{0x10, 0x10, 0x10, 0xff}, // empty
+	color.RGBA{0x80, 0x80, 0x80, 0xff}, // wall
+	color.RGBA{0x30, 0x60, 0xd0, 0xff}, // block
+	color.RGBA{0xf0, 0xf0, 0xf0, 0xff}, // paddle
+	color.RGBA{0xe0, 0x30, 0x30, 0xff}, // ball
+	color.RGBA{0xff, 0x00, 0xff, 0xff}, // unknown
+}
+
+// screen is the framebuffer of an arcade program, built from its (x, y, tile) output
+// triples. The triple (-1, 0, score) sets the score instead of drawing a tile.
+type screen struct {
+	tiles        *grid[int]
+	score        int
+	ball, paddle point
+
+	pending []int // output values of an incomplete triple
+	frames  []frame
+}
+
+// frame is a snapshot of the framebuffer: a row-major grid of tile ids
+// covering width by height cells from min.
+type frame struct {
+	min           point
+	width, height int
+	tiles         []int
+}
+
+func newScreen() *screen {
+	return &screen{tiles: newGrid[int]()}
+}
+
+// output is a machine output handler that collects triples into the framebuffer.
+func (s *screen) output(val int) error {
+	s.pending = append(s.pending, val)
+	if len(s.pending) < 3 {
+		return nil
+	}
+	x, y, tile := s.pending[0], s.pending[1], s.pending[2]
+	s.pending = s.pending[:0]
+	if x == -1 && y == 0 {
+		s.score = tile
+		return nil
+	}
+	s.tiles.set(point{x, y}, tile)
+	switch tile {
+	case tileBall:
+		s.ball = point{x, y}
+	case tilePaddle:
+		s.paddle = point{x, y}
+	}
+	return nil
+}
+
+func (s *screen) width() int {
+	return s.tiles.width()
+}
+
+func (s *screen) height() int {
+	return s.tiles.height()
+}
+
+// count returns how many cells currently show tile.
+func (s *screen) count(tile int) int {
+	n := 0
+	for _, t := range s.tiles.cells {
+		if t == tile {
+			n++
+		}
+	}
+	return n
+}
+
+// snapshot returns the framebuffer as it is now.
+func (s *screen) snapshot() frame {
+	f := frame{min: s.tiles.min, width: s.width(), height: s.height()}
+	f.tiles = make([]int, f.width*f.height)
+	for p, tile := range s.tiles.cells {
+		f.tiles[(p.y-f.min.y)*f.width+p.x-f.min.x] = tile
+	}
+	return f
+}
+
+// render draws the framebuffer and score with one palette character per tile id.
+func (s *screen) render(palette string) string {
+	chars := []rune(palette)
+	tiles := s.tiles.render(func(_ point, tile int, _ bool) rune {
+		if tile >= 0 && tile < len(chars) {
+			return chars[tile]
+		}
+		return '?'
+	})
+	return fmt.Sprintf("%sScore: %d\n", tiles, s.score)
+}
+
+func tileColor(tile int) color.Color {
+	if tile < 0 || tile >= len(tileColors)-1 {
+		return tileColors[len(tileColors)-1]
+	}
+	return tileColors[tile]
+}
+
+// frameImage draws a snapshot at the current screen size with each tile scale
+// pixels wide. Cells outside the snapshot are left empty, and an empty screen
+// is a single empty pixel.
+func (s *screen) frameImage(f frame, scale int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, max(1, s.width()*scale), max(1, s.height()*scale)), tileColors)
+	for i, tile := range f.tiles {
+		x, y := f.min.x-s.tiles.min.x+i%f.width, f.min.y-s.tiles.min.y+i/f.width
+		c := tileColor(tile)
+		for dy := 0; dy < scale; dy++ {
+			for dx := 0; dx < scale; dx++ {
+				img.Set(x*scale+dx, y*scale+dy, c)
+			}
+		}
+	}
+	return img
+}
+
+// writePNG saves the current framebuffer as a PNG.
+func (s *screen) writePNG(w io.Writer, scale int) error {
+	return png.Encode(w, s.frameImage(s.snapshot(), scale))
+}
+
+// writeGIF saves every recorded frame as an animated GIF. Frames recorded
+// while the screen was smaller are padded out to the final screen size.
+func (s *screen) writeGIF(w io.Writer, scale, delay int) error {
+	anim := &gif.GIF{}
+	for _, frame := range s.frames {
+		anim.Image = append(anim.Image, s.frameImage(frame, scale))
+		anim.Delay = append(anim.Delay, delay)
+	}
+	if len(anim.Image) == 0 {
+		anim.Image = append(anim.Image, s.frameImage(s.snapshot(), scale))
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// arcadeOptions configures playArcade.
+type arcadeOptions struct {
+	freePlay bool      // write 2 to address 0 before starting, as the puzzle does for free play
+	palette  string    // terminal characters for each tile id
+	display  io.Writer // where frames are drawn on each joystick read; nil runs headless
+	record   bool      // keep a snapshot of every frame for writeGIF
+}
+
+// playArcade runs an arcade program to completion, steering the paddle
+// towards the ball whenever the program reads the joystick.
+func playArcade(program []int, opts arcadeOptions) (*screen, error) {
+	memory := make([]int, len(program))
+	copy(memory, program)
+	if opts.freePlay && len(memory) > 0 {
+		memory[0] = 2
+	}
+	s := newScreen()
+	m := newMachine(memory)
+	m.maxMemory = extendedMemory
+	m.output = s.output
+	m.input = func() (int, error) {
+		if opts.record && s.tiles.len() > 0 {
+			s.frames = append(s.frames, s.snapshot())
+		}
+		if opts.display != nil {
+			// Move the cursor home so each frame overwrites the last.
+			fmt.Fprint(opts.display, "\033[H\033[2J"+s.render(opts.palette))
+		}
+		switch {
+		case s.ball.x < s.paddle.x:
+			return -1, nil
+		case s.ball.x > s.paddle.x:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	if err := m.run(); err != nil {
+		return s, err
+	}
+	if opts.record && s.tiles.len() > 0 {
+		s.frames = append(s.frames, s.snapshot())
+	}
+	return s, nil
+}
+
+// runArcade plays the arcade program in fileName and writes any requested images.
+func runArcade(fileName string, opts arcadeOptions, pngFile, gifFile string, scale int) error {
+	if scale < 1 && (pngFile != "" || gifFile != "") {
+		return fmt.Errorf("-scale must be at least 1, got %d", scale)
+	}
+	program, err := parseInput(fileName)
+	if err != nil {
+		return err
+	}
+	opts.record = gifFile != ""
+	s, err := playArcade(program, opts)
+	if err != nil {
+		return err
+	}
+	if s.tiles.len() == 0 {
+		fmt.Println("The program drew nothing")
+	}
+	fmt.Print(s.render(opts.palette))
+	fmt.Printf("Blocks: %d\n", s.count(tileBlock))
+	save := func(fileName string, write func(io.Writer) error) error {
+		file, err := os.Create(fileName)
+		if err != nil {
+			return err
+		}
+		if err := write(file); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+	if pngFile != "" {
+		if err := save(pngFile, func(w io.Writer) error { return s.writePNG(w, scale) }); err != nil {
+			return err
+		}
+	}
+	if gifFile != "" {
+		if err := save(gifFile, func(w io.Writer) error { return s.writeGIF(w, scale, 2) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
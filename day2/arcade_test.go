@@ -0,0 +1,134 @@
+package day2
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// arcadeProgram draws a wall and a block, reads the joystick once, then draws
+// a paddle and ball that grow the screen, sets the score to 42 and halts.
+var arcadeProgram = []int{
+	104, 0, 104, 0, 104, tileWall,
+	104, 1, 104, 0, 104, tileBlock,
+	3, 100,
+	104, 2, 104, 1, 104, tilePaddle,
+	104, 1, 104, 1, 104, tileBall,
+	104, -1, 104, 0, 104, 42,
+	99,
+}
+
+func TestPlayArcadeHeadless(t *testing.T) {
+	s, err := playArcade(arcadeProgram, arcadeOptions{palette: defaultPalette, record: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.score != 42 {
+		t.Errorf("score = %d, want 42", s.score)
+	}
+	for tile, want := range map[int]int{tileEmpty: 0, tileWall: 1, tileBlock: 1, tilePaddle: 1, tileBall: 1} {
+		if got := s.count(tile); got != want {
+			t.Errorf("count(%d) = %d, want %d", tile, got, want)
+		}
+	}
+	if s.ball != (point{1, 1}) || s.paddle != (point{2, 1}) {
+		t.Errorf("ball at %v and paddle at %v, want {1 1} and {2 1}", s.ball, s.paddle)
+	}
+	if want := "#= \n o_\nScore: 42\n"; s.render(defaultPalette) != want {
+		t.Errorf("render = %q, want %q", s.render(defaultPalette), want)
+	}
+	if len(s.frames) != 2 {
+		t.Fatalf("recorded %d frames, want one per joystick read and one at the end", len(s.frames))
+	}
+}
+
+func TestArcadeImages(t *testing.T) {
+	s, err := playArcade(arcadeProgram, arcadeOptions{record: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const scale = 4
+
+	var buf bytes.Buffer
+	if err := s.writePNG(&buf, scale); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 3*scale || b.Dy() != 2*scale {
+		t.Errorf("PNG is %dx%d, want %dx%d", b.Dx(), b.Dy(), 3*scale, 2*scale)
+	}
+	if !sameColor(img.At(2*scale, 1*scale), tileColors[tilePaddle]) {
+		t.Errorf("PNG paddle cell is %v", img.At(2*scale, 1*scale))
+	}
+
+	buf.Reset()
+	if err := s.writeGIF(&buf, scale, 2); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 {
+		t.Fatalf("GIF has %d frames, want 2", len(anim.Image))
+	}
+	for i, frame := range anim.Image {
+		if b := frame.Bounds(); b.Dx() != 3*scale || b.Dy() != 2*scale {
+			t.Errorf("GIF frame %d is %dx%d, want the final screen size %dx%d", i, b.Dx(), b.Dy(), 3*scale, 2*scale)
+		}
+	}
+	// The first frame was recorded before the paddle was drawn.
+	if !sameColor(anim.Image[0].At(2*scale, 1*scale), tileColors[tileEmpty]) {
+		t.Errorf("first frame paddle cell is %v, want empty", anim.Image[0].At(2*scale, 1*scale))
+	}
+	if !sameColor(anim.Image[0].At(1*scale, 0), tileColors[tileBlock]) {
+		t.Errorf("first frame block cell is %v", anim.Image[0].At(1*scale, 0))
+	}
+	if !sameColor(anim.Image[1].At(2*scale, 1*scale), tileColors[tilePaddle]) {
+		t.Errorf("last frame paddle cell is %v", anim.Image[1].At(2*scale, 1*scale))
+	}
+}
+
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+func TestArcadeEmptyScreen(t *testing.T) {
+	s, err := playArcade([]int{99}, arcadeOptions{record: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := s.writePNG(&buf, 4); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 1 || b.Dy() != 1 {
+		t.Errorf("empty screen PNG is %dx%d, want 1x1", b.Dx(), b.Dy())
+	}
+	buf.Reset()
+	if err := s.writeGIF(&buf, 4, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gif.DecodeAll(&buf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunArcadeScale(t *testing.T) {
+	err := runArcade("missing.txt", arcadeOptions{}, "screen.png", "", 0)
+	if err == nil || !strings.Contains(err.Error(), "-scale must be at least 1") {
+		t.Errorf("runArcade with scale 0 = %v, want a scale error", err)
+	}
+}
@@ -0,0 +1,304 @@
+package day2
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// conformanceCase is one Intcode program together with what running it must produce.
+// It is loaded from a ".case" file of "key: value" lines, for example:
+//
+//	# 1 + 1 = 2
+//	program: 1,0,0,0,99
+//	inputs:
+//	outputs:
+//	memory: 2,0,0,0,99
+//
+// Lines starting with # are comments. Omitting "memory" skips the final memory check.
+// A case expecting the program to fail has an "error" line instead, whose text
+// the interpreter's error must contain; its outputs are still checked.
+type conformanceCase struct {
+	name        string
+	program     []int
+	inputs      []int
+	outputs     []int
+	memory      []int
+	checkMemory bool
+	err         string
+}
+
+// interpreter runs program with the given inputs and returns its outputs and final memory.
+type interpreter func(program, inputs []int) (outputs, memory []int, err error)
+
+func parseIntList(text string) ([]int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	var values []int
+	for _, field := range strings.Split(text, ",") {
+		val, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", field)
+		}
+		values = append(values, val)
+	}
+	return values, nil
+}
+
+func formatIntList(values []int) string {
+	fields := make([]string, len(values))
+	for i, val := range values {
+		fields[i] = strconv.Itoa(val)
+	}
+	return strings.Join(fields, ",")
+}
+
+func readConformanceCase(fileName string) (conformanceCase, error) {
+	c := conformanceCase{name: strings.TrimSuffix(filepath.Base(fileName), ".case")}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return c, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	hasProgram := false
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return c, fmt.Errorf("%s:%d: expected \"key: value\"", fileName, lineNumber)
+		}
+		if strings.TrimSpace(key) == "error" {
+			c.err = strings.TrimSpace(value)
+			continue
+		}
+		values, err := parseIntList(value)
+		if err != nil {
+			return c, fmt.Errorf("%s:%d: %v", fileName, lineNumber, err)
+		}
+		switch strings.TrimSpace(key) {
+		case "program":
+			c.program = values
+			hasProgram = true
+		case "inputs":
+			c.inputs = values
+		case "outputs":
+			c.outputs = values
+		case "memory":
+			c.memory = values
+			c.checkMemory = true
+		default:
+			return c, fmt.Errorf("%s:%d: unknown key %q", fileName, lineNumber, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return c, err
+	}
+	if !hasProgram {
+		return c, fmt.Errorf("%s: missing program", fileName)
+	}
+	return c, nil
+}
+
+// readConformanceSuite loads every ".case" file in dir, sorted by name.
+func readConformanceSuite(dir string) ([]conformanceCase, error) {
+	fileNames, err := filepath.Glob(filepath.Join(dir, "*.case"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(fileNames)
+	var cases []conformanceCase
+	for _, fileName := range fileNames {
+		c, err := readConformanceCase(fileName)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// check runs the case on run and describes the first mismatch, if any.
+func (c conformanceCase) check(run interpreter) error {
+	outputs, memory, err := run(c.program, c.inputs)
+	switch {
+	case c.err == "" && err != nil:
+		return err
+	case c.err != "" && err == nil:
+		return fmt.Errorf("got no error, want one containing %q", c.err)
+	case c.err != "" && !strings.Contains(err.Error(), c.err):
+		return fmt.Errorf("error: got %q, want one containing %q", err, c.err)
+	}
+	if !equalInts(outputs, c.outputs) {
+		return fmt.Errorf("outputs: got [%s], want [%s]", formatIntList(outputs), formatIntList(c.outputs))
+	}
+	if c.checkMemory && !equalInts(memory, c.memory) {
+		return fmt.Errorf("memory: got [%s], want [%s]", formatIntList(memory), formatIntList(c.memory))
+	}
+	return nil
+}
+
+// runConformance checks every case against run, reporting to w, and returns the number of failures.
+func runConformance(cases []conformanceCase, run interpreter, w io.Writer) int {
+	failures := 0
+	for _, c := range cases {
+		if err := c.check(run); err != nil {
+			failures++
+			fmt.Fprintf(w, "FAIL %s: %v\n", c.name, err)
+			continue
+		}
+		fmt.Fprintf(w, "ok   %s\n", c.name)
+	}
+	fmt.Fprintf(w, "%d/%d cases passed\n", len(cases)-failures, len(cases))
+	return failures
+}
+
+// builtinInterpreter runs program on this package's Intcode machine.
+func builtinInterpreter(program, inputs []int) ([]int, []int, error) {
+	memory := make([]int, len(program))
+	copy(memory, program)
+	m := newMachine(memory)
+	m.maxMemory = extendedMemory
+	m.input = sliceInput(inputs)
+	var outputs []int
+	m.output = func(val int) error {
+		outputs = append(outputs, val)
+		return nil
+	}
+	if err := m.run(); err != nil {
+		return outputs, m.memory, err
+	}
+	return outputs, m.memory, nil
+}
+
+// commandInterpreter runs an external interpreter as a child process. The
+// program is written to its stdin as the first line, followed by one input per
+// line. It must print one output per line followed by a final line holding
+// either the comma separated memory after the program halts or, if the program
+// failed, "error: " and a message. "aoc intcode -exec" speaks this protocol.
+func commandInterpreter(commandLine string) interpreter {
+	return func(program, inputs []int) ([]int, []int, error) {
+		args := strings.Fields(commandLine)
+		if len(args) == 0 {
+			return nil, nil, fmt.Errorf("empty interpreter command")
+		}
+		var stdin, stderr bytes.Buffer
+		fmt.Fprintln(&stdin, formatIntList(program))
+		for _, input := range inputs {
+			fmt.Fprintln(&stdin, input)
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = &stdin
+		cmd.Stderr = &stderr
+		out, runErr := cmd.Output()
+
+		var lines []string
+		if len(out) > 0 {
+			lines = strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+		}
+		if runErr != nil {
+			// Keep whatever the interpreter output before it failed.
+			outputs, _ := parseOutputs(lines)
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return outputs, nil, fmt.Errorf("%s: %v: %s", commandLine, runErr, msg)
+			}
+			return outputs, nil, fmt.Errorf("%s: %v", commandLine, runErr)
+		}
+		if len(lines) == 0 {
+			return nil, nil, fmt.Errorf("%s: no final memory", commandLine)
+		}
+		last := lines[len(lines)-1]
+		outputs, err := parseOutputs(lines[:len(lines)-1])
+		if err != nil {
+			return outputs, nil, fmt.Errorf("%s: %v", commandLine, err)
+		}
+		if msg, ok := strings.CutPrefix(last, "error: "); ok {
+			return outputs, nil, errors.New(msg)
+		}
+		memory, err := parseIntList(last)
+		if err != nil {
+			return outputs, nil, fmt.Errorf("%s: final memory: %v", commandLine, err)
+		}
+		return outputs, memory, nil
+	}
+}
+
+// parseOutputs parses one output per line, returning those before the first invalid line.
+func parseOutputs(lines []string) ([]int, error) {
+	var outputs []int
+	for _, line := range lines {
+		val, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil {
+			return outputs, fmt.Errorf("invalid output %q", line)
+		}
+		outputs = append(outputs, val)
+	}
+	return outputs, nil
+}
+
+// serveExec is the interpreter side of the commandInterpreter protocol for the builtin machine.
+func serveExec(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 1<<24)
+	if !scanner.Scan() {
+		return fmt.Errorf("missing program")
+	}
+	program, err := parseIntList(scanner.Text())
+	if err != nil {
+		return err
+	}
+	var inputs []int
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		val, err := strconv.Atoi(line)
+		if err != nil {
+			return fmt.Errorf("invalid input %q", line)
+		}
+		inputs = append(inputs, val)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	outputs, memory, err := builtinInterpreter(program, inputs)
+	for _, val := range outputs {
+		fmt.Fprintln(out, val)
+	}
+	if err != nil {
+		// The program failed, not the interpreter, so report it in the protocol.
+		_, writeErr := fmt.Fprintf(out, "error: %s\n", strings.ReplaceAll(err.Error(), "\n", " "))
+		return writeErr
+	}
+	_, err = fmt.Fprintln(out, formatIntList(memory))
+	return err
+}
@@ -0,0 +1,69 @@
+package day2
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	cases, err := readConformanceSuite("conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatal("no conformance cases found")
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.check(builtinInterpreter); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestExecHelper is the child process of TestConformanceExec: it serves the
+// -exec protocol when the test binary is rerun with AOC_EXEC_HELPER set.
+func TestExecHelper(t *testing.T) {
+	switch os.Getenv("AOC_EXEC_HELPER") {
+	case "":
+		t.Skip("only run as a child of TestConformanceExec")
+	case "fail":
+		os.Stderr.WriteString("interpreter crashed")
+		os.Exit(2)
+	}
+	if err := serveExec(os.Stdin, os.Stdout); err != nil {
+		os.Stderr.WriteString(err.Error())
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+func TestConformanceExec(t *testing.T) {
+	cases, err := readConformanceSuite("conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AOC_EXEC_HELPER", "1")
+	run := commandInterpreter(os.Args[0] + " -test.run=^TestExecHelper$")
+	var report strings.Builder
+	if failures := runConformance(cases, run, &report); failures > 0 {
+		t.Errorf("%d cases failed through -exec:\n%s", failures, report.String())
+	}
+
+	// Outputs before an error are kept.
+	outputs, _, err := run([]int{104, 7, 1105, 1, -1}, nil)
+	if err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("error = %v, want one containing \"out of range\"", err)
+	}
+	if len(outputs) != 1 || outputs[0] != 7 {
+		t.Errorf("outputs = %v, want [7]", outputs)
+	}
+
+	// A failing interpreter's exit status and stderr are part of the error.
+	t.Setenv("AOC_EXEC_HELPER", "fail")
+	if _, _, err := run([]int{99}, nil); err == nil || !strings.Contains(err.Error(), "exit status 2: interpreter crashed") {
+		t.Errorf("error = %v, want the interpreter's exit status and stderr", err)
+	}
+}
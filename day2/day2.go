@@ -1,11 +1,12 @@
-package main
+package day2
 
 import (
+	"flag"
 	"os"
-	"encoding/csv"
-	"bufio"
 	"strconv"
 	"fmt"
+
+	"github.com/cquon/aoc-2019/solution"
 )
 
 /*
@@ -77,53 +78,17 @@ Find the input noun and verb that cause the program to produce the output 196907
 */
 
 func parseInput(fileName string) ([]int, error) {
-	csvFile, _ := os.Open(fileName)
-	reader := csv.NewReader(bufio.NewReader(csvFile))
-	records, err := reader.Read()
-	output := make([]int, len(records))
+	data, err := os.ReadFile(fileName)
 	if err != nil {
 		return nil, err
 	}
-	for i, record := range records {
-		val, err := strconv.Atoi(record)
-		if err != nil {
-			panic("Invalid non integer in array.")
-		}
-		output[i] = val
-	}
-	return output, nil
-}
-
-// doNextOperation does operation on the input array "input" starting at index "index", and returns whether it is terminated or not
-func doNextOperation(input []int, index int) bool {
-	if index >= len(input) {
-		return true
-	}
-	op := input[index]
-	switch op {
-	case 1:	// addition
-		val1 := input[input[index+1]]
-		val2 := input[input[index+2]]
-		input[input[index+3]] = val1 + val2
-		return false
-	case 2: // multiplication
-		val1 := input[input[index+1]]
-		val2 := input[input[index+2]]
-		input[input[index+3]] = val1 * val2
-		return false
-	case 99: // finished
-		return true
-	}
-	return true
+	return parseIntList(string(data))
 }
 
+// calculate runs the program in place and returns the value left at position 0.
+// A program that crashes (unknown opcode or bad address) just stops where it failed.
 func calculate(input []int) int {
-	for i:=0;i<len(input);i+=4 {
-		isFinished := doNextOperation(input, i)
-		if isFinished {
-			break
-		}
-	}
+	newMachine(input).run()
 	return input[0]
 }
 
@@ -132,29 +97,129 @@ func modInput(inOutArray []int, noun, verb int) {
 	inOutArray[2] = verb
 }
 
-func main() {
-	program, err := parseInput("input.txt")
-	if err != nil {
-		panic("malformed input.")
-	}
+// gravityAssist is the day 2 solution.
+type gravityAssist struct{}
 
-	// replace position 1 with the value 12 and replace position 2 with the value 2
+// run copies the program, sets the noun and verb and returns the value left at position 0.
+func (gravityAssist) run(program []int, noun, verb int) (int, error) {
+	if len(program) < 3 {
+		return 0, fmt.Errorf("program has %d values, need at least 3", len(program))
+	}
 	copiedProgram := make([]int, len(program))
 	copy(copiedProgram, program)
-	modInput(copiedProgram, 12, 2)
+	modInput(copiedProgram, noun, verb)
+	return calculate(copiedProgram), nil
+}
 
-	// Part 1:
-	fmt.Printf("Part 1: %d\n", calculate(copiedProgram))
+func (g gravityAssist) Part1(input string) (string, error) {
+	program, err := parseIntList(input)
+	if err != nil {
+		return "", err
+	}
+	// replace position 1 with the value 12 and replace position 2 with the value 2
+	output, err := g.run(program, 12, 2)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(output), nil
+}
 
-	// Part 2:
+func (g gravityAssist) Part2(input string) (string, error) {
+	program, err := parseIntList(input)
+	if err != nil {
+		return "", err
+	}
 	for i:=0; i<=99; i++ {
 		for j:=0; j<=99; j++ {
-			copiedProgram := make([]int, len(program))
-			copy(copiedProgram, program)
-			modInput(copiedProgram, i, j)
-			if calculate(copiedProgram) == 19690720 {
-				fmt.Printf("Part 2: %d\n", 100 * i + j)
+			output, err := g.run(program, i, j)
+			if err != nil {
+				return "", err
 			}
+			if output == 19690720 {
+				return strconv.Itoa(100 * i + j), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no noun and verb produce 19690720")
+}
+
+func init() {
+	solution.Register(2, gravityAssist{})
+}
+
+// Tool runs the Intcode tools (debugger, conformance checker, arcade and droid)
+// selected by command line style args.
+func Tool(args []string) error {
+	flags := flag.NewFlagSet("intcode", flag.ContinueOnError)
+	inputFile := flags.String("input", "day2/input.txt", "debugger: gravity assist program")
+	debug := flags.Bool("debug", false, "step through the 1202 program in the time-travel debugger")
+	checkpointInterval := flags.Int("checkpoint-interval", 1000, "debugger: steps between checkpoints")
+	maxCheckpoints := flags.Int("max-checkpoints", 100, "debugger: checkpoints (and undo history) to keep")
+	conformance := flags.String("conformance", "", "check an interpreter against the case files in this directory")
+	interpreterCommand := flags.String("interpreter", "", "conformance: external interpreter command to check instead of the builtin one")
+	execMode := flags.Bool("exec", false, "run a program read from stdin, speaking the -interpreter protocol")
+	arcade := flags.String("arcade", "", "play the arcade program in this file")
+	freePlay := flags.Bool("free-play", false, "arcade: insert quarters (set address 0 to 2)")
+	palette := flags.String("palette", defaultPalette, "arcade: characters for tile ids 0, 1, 2, ...")
+	headless := flags.Bool("headless", false, "arcade: don't draw frames while playing")
+	pngFile := flags.String("png", "", "arcade: save the final screen as a PNG")
+	gifFile := flags.String("gif", "", "arcade: save every frame as an animated GIF")
+	scale := flags.Int("scale", 8, "arcade: pixels per tile in saved images")
+	droidProgram := flags.String("droid", "", "explore with the droid program in this file")
+	strategyName := flags.String("strategy", "dfs", "droid: exploration strategy, dfs or bfs")
+	maxMoves := flags.Int("max-moves", 0, "droid: give up after this many moves (0 for no limit)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case *droidProgram != "":
+		return runDroid(*droidProgram, *strategyName, *maxMoves)
+	case *arcade != "":
+		opts := arcadeOptions{freePlay: *freePlay, palette: *palette}
+		if !*headless {
+			opts.display = os.Stdout
+		}
+		return runArcade(*arcade, opts, *pngFile, *gifFile, *scale)
+	case *execMode:
+		return serveExec(os.Stdin, os.Stdout)
+	case *conformance != "":
+		cases, err := readConformanceSuite(*conformance)
+		if err != nil {
+			return err
 		}
+		run := interpreter(builtinInterpreter)
+		if *interpreterCommand != "" {
+			run = commandInterpreter(*interpreterCommand)
+		}
+		if failures := runConformance(cases, run, os.Stdout); failures > 0 {
+			return fmt.Errorf("%d conformance cases failed", failures)
+		}
+		return nil
+	case *debug:
+		program, err := parseInput(*inputFile)
+		if err != nil {
+			return err
+		}
+		if len(program) < 3 {
+			return fmt.Errorf("program has %d values, need at least 3", len(program))
+		}
+		modInput(program, 12, 2)
+		newDebugger(program, *checkpointInterval, *maxCheckpoints).repl(os.Stdin, os.Stdout)
+		return nil
 	}
-}
\ No newline at end of file
+	flags.Usage()
+	return fmt.Errorf("no tool selected")
+}
+
+// Validate checks the input is a comma separated program long enough to take a noun and verb.
+func (gravityAssist) Validate(input string) error {
+	program, err := parseIntList(input)
+	if err != nil {
+		return err
+	}
+	if len(program) < 3 {
+		return fmt.Errorf("program has %d values, need at least 3", len(program))
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package day2
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// checkpoint is a full copy of the machine state taken before step "step" ran.
+type checkpoint struct {
+	step         int
+	memory       []int
+	ip           int
+	relativeBase int
+}
+
+// debugger runs an Intcode program while recording an undo log of every
+// instruction, so execution can be stepped backwards as well as forwards.
+// Memory is bounded by only keeping history back to the oldest checkpoint.
+type debugger struct {
+	m    *machine
+	step int // number of instructions executed so far
+
+	history      []effect // history[i] is the effect of step historyStart+i
+	historyStart int
+
+	checkpoints        []checkpoint
+	checkpointInterval int
+	maxCheckpoints     int
+}
+
+// newDebugger returns a debugger for a copy of program which takes a checkpoint every
+// interval steps and keeps at most maxCheckpoints of them.
+func newDebugger(program []int, interval, maxCheckpoints int) *debugger {
+	if interval < 1 {
+		interval = 1
+	}
+	if maxCheckpoints < 1 {
+		maxCheckpoints = 1
+	}
+	memory := make([]int, len(program))
+	copy(memory, program)
+	d := &debugger{
+		m:                  newMachine(memory),
+		checkpointInterval: interval,
+		maxCheckpoints:     maxCheckpoints,
+	}
+	d.takeCheckpoint()
+	return d
+}
+
+func (d *debugger) takeCheckpoint() {
+	memory := make([]int, len(d.m.memory))
+	copy(memory, d.m.memory)
+	d.checkpoints = append(d.checkpoints, checkpoint{step: d.step, memory: memory, ip: d.m.ip, relativeBase: d.m.relativeBase})
+	if len(d.checkpoints) <= d.maxCheckpoints {
+		return
+	}
+	// Forget everything before the new oldest checkpoint.
+	d.checkpoints = d.checkpoints[1:]
+	oldest := d.checkpoints[0].step
+	d.history = append([]effect(nil), d.history[oldest-d.historyStart:]...)
+	d.historyStart = oldest
+}
+
+// oldestStep is the earliest step the debugger can still go back to.
+func (d *debugger) oldestStep() int {
+	return d.checkpoints[0].step
+}
+
+// recorded is the number of steps executed so far, including any undone ones that can be redone.
+func (d *debugger) recorded() int {
+	return d.historyStart + len(d.history)
+}
+
+// forward executes (or redoes) up to n instructions and returns how many ran.
+func (d *debugger) forward(n int) (int, error) {
+	for i := 0; i < n; i++ {
+		if d.step < d.recorded() {
+			d.m.apply(d.history[d.step-d.historyStart])
+			d.step++
+			continue
+		}
+		if d.m.halted {
+			return i, nil
+		}
+		if d.step > 0 && d.step%d.checkpointInterval == 0 && d.checkpoints[len(d.checkpoints)-1].step != d.step {
+			d.takeCheckpoint()
+		}
+		e, err := d.m.step()
+		if err != nil {
+			return i, err
+		}
+		if e.halted {
+			return i, nil
+		}
+		d.history = append(d.history, e)
+		d.step++
+	}
+	return n, nil
+}
+
+// back undoes up to n instructions and returns how many were undone.
+func (d *debugger) back(n int) int {
+	for i := 0; i < n; i++ {
+		if d.step <= d.oldestStep() {
+			return i
+		}
+		d.step--
+		d.m.undo(d.history[d.step-d.historyStart])
+	}
+	return n
+}
+
+// seek moves to the state just before step target runs, restoring from the
+// nearest checkpoint when that is cheaper than undoing one step at a time.
+func (d *debugger) seek(target int) error {
+	if target < d.oldestStep() {
+		return fmt.Errorf("step %d is older than the oldest checkpoint (step %d)", target, d.oldestStep())
+	}
+	if target < d.step {
+		cp := d.checkpoints[0]
+		for _, c := range d.checkpoints {
+			if c.step <= target {
+				cp = c
+			}
+		}
+		if target-cp.step < d.step-target {
+			d.m.memory = append([]int(nil), cp.memory...)
+			d.m.ip = cp.ip
+			d.m.relativeBase = cp.relativeBase
+			d.m.halted = false
+			d.step = cp.step
+		} else {
+			d.back(d.step - target)
+			return nil
+		}
+	}
+	if _, err := d.forward(target - d.step); err != nil {
+		return err
+	}
+	if d.step != target {
+		return fmt.Errorf("program halted at step %d before reaching step %d", d.step, target)
+	}
+	return nil
+}
+
+// lastWrite returns the most recent step before the current one that wrote addr.
+func (d *debugger) lastWrite(addr int) (int, bool) {
+	for s := d.step - 1; s >= d.historyStart; s-- {
+		if d.history[s-d.historyStart].addr == addr {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// describe prints the position of the debugger and the instruction about to run.
+func (d *debugger) describe(w io.Writer) {
+	m := d.m
+	if m.halted {
+		fmt.Fprintf(w, "step %d: halted\n", d.step)
+		return
+	}
+	if m.ip < 0 || m.ip >= len(m.memory) {
+		fmt.Fprintf(w, "step %d: ip=%d is outside memory\n", d.step, m.ip)
+		return
+	}
+	end := m.ip + 1 + paramCounts[m.memory[m.ip]%100]
+	if end > len(m.memory) {
+		end = len(m.memory)
+	}
+	fmt.Fprintf(w, "step %d: ip=%d %v\n", d.step, m.ip, m.memory[m.ip:end])
+}
+
+const debuggerHelp = `commands:
+  s [n]      step forward n instructions (default 1)
+  b [n]      step backward n instructions (default 1)
+  g <step>   go to the state before step <step> runs
+  w <addr>   go back to just after the last write to <addr>
+  p <addr>   print the value at <addr>
+  c          continue until the program halts
+  q          quit
+`
+
+// repl drives the debugger from commands read from in.
+func (d *debugger) repl(in io.Reader, out io.Writer) {
+	scanner := bufio.NewScanner(in)
+	d.describe(out)
+	fmt.Fprint(out, "> ")
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			fmt.Fprint(out, "> ")
+			continue
+		}
+		arg := 1
+		if len(fields) > 1 {
+			val, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Fprintf(out, "bad argument %q\n> ", fields[1])
+				continue
+			}
+			arg = val
+		}
+		var err error
+		switch fields[0] {
+		case "s":
+			_, err = d.forward(arg)
+		case "b":
+			if d.back(arg) < arg {
+				fmt.Fprintf(out, "no history before step %d\n", d.oldestStep())
+			}
+		case "g":
+			err = d.seek(arg)
+		case "w":
+			if s, ok := d.lastWrite(arg); ok {
+				err = d.seek(s + 1)
+			} else {
+				fmt.Fprintf(out, "address %d not written since step %d\n", arg, d.oldestStep())
+			}
+		case "p":
+			var val int
+			if val, err = d.m.read(arg); err == nil {
+				fmt.Fprintf(out, "[%d] = %d\n", arg, val)
+			}
+		case "c":
+			for err == nil && !d.m.halted {
+				_, err = d.forward(d.checkpointInterval)
+			}
+		case "q":
+			return
+		default:
+			fmt.Fprint(out, debuggerHelp)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+		d.describe(out)
+		fmt.Fprint(out, "> ")
+	}
+}
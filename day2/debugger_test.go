@@ -0,0 +1,113 @@
+package day2
+
+import (
+	"strings"
+	"testing"
+)
+
+// counterProgram adds 1 to address 9 and jumps back forever, so before step s
+// runs the counter holds (s+1)/2 and every even step writes address 9.
+var counterProgram = []int{1001, 9, 1, 9, 1105, 1, 0, 99, 0, 0}
+
+func counterAt(step int) int {
+	return (step + 1) / 2
+}
+
+func checkCounter(t *testing.T, d *debugger, step int) {
+	t.Helper()
+	if d.step != step {
+		t.Fatalf("at step %d, want step %d", d.step, step)
+	}
+	if got := d.m.memory[9]; got != counterAt(step) {
+		t.Fatalf("step %d: counter = %d, want %d", step, got, counterAt(step))
+	}
+}
+
+func TestDebuggerForwardBack(t *testing.T) {
+	d := newDebugger(counterProgram, 10, 3)
+	if n, err := d.forward(100); n != 100 || err != nil {
+		t.Fatalf("forward(100) = %d, %v", n, err)
+	}
+	checkCounter(t, d, 100)
+	if d.oldestStep() != 70 {
+		t.Fatalf("oldest step = %d, want 70 after evicting old checkpoints", d.oldestStep())
+	}
+
+	if n := d.back(5); n != 5 {
+		t.Fatalf("back(5) = %d", n)
+	}
+	checkCounter(t, d, 95)
+	if n := d.back(100); n != 25 {
+		t.Fatalf("back(100) = %d, want to stop at the oldest checkpoint after 25", n)
+	}
+	checkCounter(t, d, 70)
+
+	// Redo the undone steps, then carry on past them.
+	if n, err := d.forward(40); n != 40 || err != nil {
+		t.Fatalf("forward(40) = %d, %v", n, err)
+	}
+	checkCounter(t, d, 110)
+	if d.oldestStep() != 80 {
+		t.Fatalf("oldest step = %d, want 80", d.oldestStep())
+	}
+}
+
+func TestDebuggerSeek(t *testing.T) {
+	d := newDebugger(counterProgram, 10, 3)
+	if err := d.seek(100); err != nil {
+		t.Fatal(err)
+	}
+	checkCounter(t, d, 100)
+	for _, target := range []int{71, 99, 85, 150, 125} {
+		if err := d.seek(target); err != nil {
+			t.Fatalf("seek(%d): %v", target, err)
+		}
+		checkCounter(t, d, target)
+	}
+	if err := d.seek(100); err == nil || !strings.Contains(err.Error(), "older than the oldest checkpoint") {
+		t.Fatalf("seek(100) after evicting it = %v, want an error", err)
+	}
+}
+
+func TestDebuggerLastWrite(t *testing.T) {
+	d := newDebugger(counterProgram, 10, 3)
+	if _, err := d.forward(100); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := d.lastWrite(9); !ok || s != 98 {
+		t.Fatalf("lastWrite(9) = %d, %v, want 98", s, ok)
+	}
+	if _, ok := d.lastWrite(0); ok {
+		t.Fatal("address 0 is never written")
+	}
+	if err := d.seek(75); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := d.lastWrite(9); !ok || s != 74 {
+		t.Fatalf("lastWrite(9) at step 75 = %d, %v, want 74", s, ok)
+	}
+	if err := d.seek(71); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := d.lastWrite(9); !ok || s != 70 {
+		t.Fatalf("lastWrite(9) at step 71 = %d, %v, want 70", s, ok)
+	}
+	if err := d.seek(70); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.lastWrite(9); ok {
+		t.Fatal("writes before the oldest checkpoint should be forgotten")
+	}
+}
+
+func TestDebuggerRunOffEnd(t *testing.T) {
+	program := []int{1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0}
+	d := newDebugger(program, 10, 3)
+	var out strings.Builder
+	d.repl(strings.NewReader("s 4\ns\nb 2\n"), &out)
+	for _, want := range []string{"step 4: ip=16 is outside memory", "step 4: halted", "step 2: ip=8 [1 0 0 0]"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package day2
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Movement commands understood by droid programs.
+const (
+	north = 1
+	south = 2
+	west  = 3
+	east  = 4
+)
+
+// Status replies from droid programs, which are also what the explored map stores.
+const (
+	cellWall   = 0 // the droid hit a wall and didn't move
+	cellOpen   = 1 // the droid moved
+	cellTarget = 2 // the droid moved and is now on the target
+)
+
+// moves maps each movement command to the change in position it causes. North is up, so y decreases.
+var moves = map[int]point{
+	north: {0, -1},
+	south: {0, 1},
+	west:  {-1, 0},
+	east:  {1, 0},
+}
+
+// directions lists the movement commands in the order strategies try them.
+var directions = []int{north, south, west, east}
+
+// droid is a controller that exchanges movement commands and status replies
+// with an Intcode program and maps the grid it explores.
+type droid struct {
+	m     *machine
+	pos   point
+	known *grid[int]
+	moved int
+
+	command    int
+	hasCommand bool
+	reply      int
+	hasReply   bool
+}
+
+func newDroid(program []int) *droid {
+	memory := make([]int, len(program))
+	copy(memory, program)
+	d := &droid{m: newMachine(memory), known: newGrid[int]()}
+	d.m.maxMemory = extendedMemory
+	d.m.input = func() (int, error) {
+		if !d.hasCommand {
+			return 0, errors.New("droid program read a second command before replying")
+		}
+		d.hasCommand = false
+		return d.command, nil
+	}
+	d.m.output = func(val int) error {
+		d.reply, d.hasReply = val, true
+		return nil
+	}
+	d.known.set(point{}, cellOpen)
+	return d
+}
+
+// send gives the program one movement command and runs it until it replies.
+func (d *droid) send(command int) (int, error) {
+	d.command, d.hasCommand = command, true
+	d.hasReply = false
+	for !d.hasReply {
+		if d.m.halted {
+			return 0, errors.New("droid program halted")
+		}
+		if _, err := d.m.step(); err != nil {
+			return 0, err
+		}
+	}
+	return d.reply, nil
+}
+
+// move sends command, records what the droid found and reports whether it moved.
+func (d *droid) move(command int) (bool, error) {
+	status, err := d.send(command)
+	if err != nil {
+		return false, err
+	}
+	next := d.pos.add(moves[command])
+	switch status {
+	case cellWall:
+		d.known.set(next, cellWall)
+		return false, nil
+	case cellOpen, cellTarget:
+		d.known.set(next, status)
+		d.pos = next
+		d.moved++
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown droid status %d", status)
+}
+
+// strategy decides where a droid explores next.
+type strategy interface {
+	// next returns the commands to send from pos given the map so far. No commands ends exploration.
+	next(known *grid[int], pos point) []int
+}
+
+// dfsStrategy explores depth first, backtracking along its own trail when it reaches a dead end.
+type dfsStrategy struct {
+	trail []point
+}
+
+func (s *dfsStrategy) next(known *grid[int], pos point) []int {
+	switch n := len(s.trail); {
+	case n >= 2 && s.trail[n-2] == pos:
+		s.trail = s.trail[:n-1]
+	case n == 0 || s.trail[n-1] != pos:
+		s.trail = append(s.trail, pos)
+	}
+	for _, dir := range directions {
+		if _, ok := known.get(pos.add(moves[dir])); !ok {
+			return []int{dir}
+		}
+	}
+	if n := len(s.trail); n >= 2 {
+		return []int{directionTo(pos, s.trail[n-2])}
+	}
+	return nil
+}
+
+// frontierStrategy walks the shortest known path to the nearest cell next to unexplored space.
+type frontierStrategy struct{}
+
+func (frontierStrategy) next(known *grid[int], pos point) []int {
+	var found []int
+	search(known, pos, func(p point, path []int) bool {
+		for _, dir := range directions {
+			if _, ok := known.get(p.add(moves[dir])); !ok {
+				found = append(path, dir)
+				return true
+			}
+		}
+		return false
+	})
+	return found
+}
+
+func directionTo(from, to point) int {
+	for _, dir := range directions {
+		if from.add(moves[dir]) == to {
+			return dir
+		}
+	}
+	return 0
+}
+
+func passable(known *grid[int], p point) bool {
+	cell, ok := known.get(p)
+	return ok && cell != cellWall
+}
+
+// search does a breadth first search over the passable cells of known from start,
+// calling visit with each cell and the commands that reach it until visit returns true.
+func search(known *grid[int], start point, visit func(p point, path []int) bool) {
+	paths := map[point][]int{start: nil}
+	queue := []point{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if visit(p, paths[p]) {
+			return
+		}
+		for _, dir := range directions {
+			next := p.add(moves[dir])
+			if _, seen := paths[next]; seen || !passable(known, next) {
+				continue
+			}
+			paths[next] = append(append([]int(nil), paths[p]...), dir)
+			queue = append(queue, next)
+		}
+	}
+}
+
+// explore drives the droid with s until the strategy is done or maxMoves
+// commands have been sent. Zero maxMoves means no limit.
+func (d *droid) explore(s strategy, maxMoves int) error {
+	sent := 0
+	for {
+		commands := s.next(d.known, d.pos)
+		if len(commands) == 0 {
+			return nil
+		}
+		for _, command := range commands {
+			if maxMoves > 0 && sent >= maxMoves {
+				return fmt.Errorf("gave up after %d moves", sent)
+			}
+			sent++
+			moved, err := d.move(command)
+			if err != nil {
+				return err
+			}
+			if !moved {
+				break
+			}
+		}
+	}
+}
+
+// shortestPath returns the commands for the shortest path between two cells of the explored map.
+func shortestPath(known *grid[int], from, to point) ([]int, bool) {
+	var found []int
+	ok := false
+	search(known, from, func(p point, path []int) bool {
+		if p == to {
+			found, ok = path, true
+		}
+		return ok
+	})
+	return found, ok
+}
+
+// farthest returns the largest shortest-path distance from start to any reachable cell.
+func farthest(known *grid[int], start point) int {
+	longest := 0
+	search(known, start, func(_ point, path []int) bool {
+		longest = max(longest, len(path))
+		return false
+	})
+	return longest
+}
+
+// findCell returns a cell of the explored map holding val.
+func findCell(known *grid[int], val int) (point, bool) {
+	for p, cell := range known.cells {
+		if cell == val {
+			return p, true
+		}
+	}
+	return point{}, false
+}
+
+// renderMap draws the explored map with the droid's start marked.
+func renderMap(known *grid[int]) string {
+	return known.render(func(p point, cell int, ok bool) rune {
+		switch {
+		case !ok:
+			return ' '
+		case p == point{}:
+			return 'S'
+		case cell == cellWall:
+			return '#'
+		case cell == cellTarget:
+			return 'T'
+		}
+		return '.'
+	})
+}
+
+// runDroid explores with the droid program in fileName and answers path queries about the map.
+func runDroid(fileName, strategyName string, maxMoves int) error {
+	program, err := parseInput(fileName)
+	if err != nil {
+		return err
+	}
+	var s strategy
+	switch strategyName {
+	case "dfs":
+		s = &dfsStrategy{}
+	case "bfs":
+		s = frontierStrategy{}
+	default:
+		return fmt.Errorf("unknown strategy %q, want dfs or bfs", strategyName)
+	}
+	d := newDroid(program)
+	if err := d.explore(s, maxMoves); err != nil {
+		return err
+	}
+	fmt.Print(renderMap(d.known))
+	fmt.Printf("Explored %d cells in %d moves\n", d.known.len(), d.moved)
+	target, ok := findCell(d.known, cellTarget)
+	if !ok {
+		fmt.Println("Target not found")
+		return nil
+	}
+	path, _ := shortestPath(d.known, point{}, target)
+	fmt.Printf("Shortest path to target: %d moves\n", len(path))
+	fmt.Printf("Farthest cell from target: %d moves\n", farthest(d.known, target))
+	return nil
+}
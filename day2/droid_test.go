@@ -0,0 +1,104 @@
+package day2
+
+import (
+	"strings"
+	"testing"
+)
+
+// testMaze is explored by a droid starting at S looking for T.
+var testMaze = []string{
+	"#########",
+	"#S..#...#",
+	"##.##.#.#",
+	"#..#..#T#",
+	"#.##.####",
+	"#.......#",
+	"#########",
+}
+
+// mazeDroid returns a droid whose program reads a command and replies with
+// address 8, which the test fills in from testMaze before the program reads it.
+func mazeDroid(t *testing.T) *droid {
+	t.Helper()
+	var pos point
+	for y, row := range testMaze {
+		if x := strings.IndexByte(row, 'S'); x >= 0 {
+			pos = point{x, y}
+		}
+	}
+	d := newDroid([]int{3, 7, 4, 8, 1105, 1, 0, 0, 0})
+	read := d.m.input
+	d.m.input = func() (int, error) {
+		command, err := read()
+		if err != nil {
+			return 0, err
+		}
+		next := pos.add(moves[command])
+		switch testMaze[next.y][next.x] {
+		case '#':
+			d.m.memory[8] = cellWall
+		case 'T':
+			d.m.memory[8], pos = cellTarget, next
+		default:
+			d.m.memory[8], pos = cellOpen, next
+		}
+		return command, nil
+	}
+	return d
+}
+
+// wantMap is testMaze as the droid sees it: walls it never touched are unknown.
+const wantMap = ` ### ### 
+#S..#...#
+ #.##.#.#
+#..#..#T#
+#.##.### 
+#.......#
+ ####### 
+`
+
+func TestDroidExplore(t *testing.T) {
+	for name, s := range map[string]strategy{"dfs": &dfsStrategy{}, "bfs": frontierStrategy{}} {
+		t.Run(name, func(t *testing.T) {
+			d := mazeDroid(t)
+			if err := d.explore(s, 0); err != nil {
+				t.Fatal(err)
+			}
+			if got := renderMap(d.known); got != wantMap {
+				t.Fatalf("explored map:\n%s\nwant:\n%s", got, wantMap)
+			}
+			if d.known.len() != 56 {
+				t.Errorf("explored %d cells, want 56", d.known.len())
+			}
+			target, ok := findCell(d.known, cellTarget)
+			if !ok || target != (point{6, 2}) {
+				t.Fatalf("target at %v, %v, want {6 2}", target, ok)
+			}
+			path, ok := shortestPath(d.known, point{}, target)
+			if !ok || len(path) != 18 {
+				t.Fatalf("shortest path %v, %v, want 18 moves", path, ok)
+			}
+			// Following the path from the start must end on the target.
+			p := point{}
+			for _, command := range path {
+				p = p.add(moves[command])
+				if !passable(d.known, p) {
+					t.Fatalf("path %v runs into a wall at %v", path, p)
+				}
+			}
+			if p != target {
+				t.Errorf("path %v ends at %v, want %v", path, p, target)
+			}
+			if got := farthest(d.known, target); got != 18 {
+				t.Errorf("farthest cell from the target is %d moves, want 18", got)
+			}
+		})
+	}
+}
+
+func TestDroidGivesUp(t *testing.T) {
+	d := mazeDroid(t)
+	if err := d.explore(&dfsStrategy{}, 5); err == nil || !strings.Contains(err.Error(), "gave up after 5 moves") {
+		t.Fatalf("explore with 5 moves = %v, want to give up", err)
+	}
+}
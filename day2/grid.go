@@ -0,0 +1,71 @@
+package day2
+
+import (
+	"strings"
+)
+
+type point struct {
+	x, y int
+}
+
+func (p point) add(q point) point {
+	return point{p.x + q.x, p.y + q.y}
+}
+
+// grid is a sparse 2D grid of cells that keeps track of the bounds of every cell set so far.
+type grid[T any] struct {
+	cells    map[point]T
+	min, max point
+}
+
+func newGrid[T any]() *grid[T] {
+	return &grid[T]{cells: make(map[point]T)}
+}
+
+func (g *grid[T]) get(p point) (T, bool) {
+	val, ok := g.cells[p]
+	return val, ok
+}
+
+func (g *grid[T]) set(p point, val T) {
+	if len(g.cells) == 0 {
+		g.min, g.max = p, p
+	}
+	g.min.x = min(g.min.x, p.x)
+	g.min.y = min(g.min.y, p.y)
+	g.max.x = max(g.max.x, p.x)
+	g.max.y = max(g.max.y, p.y)
+	g.cells[p] = val
+}
+
+func (g *grid[T]) len() int {
+	return len(g.cells)
+}
+
+func (g *grid[T]) width() int {
+	if len(g.cells) == 0 {
+		return 0
+	}
+	return g.max.x - g.min.x + 1
+}
+
+func (g *grid[T]) height() int {
+	if len(g.cells) == 0 {
+		return 0
+	}
+	return g.max.y - g.min.y + 1
+}
+
+// render draws the grid row by row, top (smallest y) first, using draw for every cell in the bounds.
+func (g *grid[T]) render(draw func(p point, val T, ok bool) rune) string {
+	var b strings.Builder
+	for y := g.min.y; y <= g.max.y && len(g.cells) > 0; y++ {
+		for x := g.min.x; x <= g.max.x; x++ {
+			p := point{x, y}
+			val, ok := g.cells[p]
+			b.WriteRune(draw(p, val, ok))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
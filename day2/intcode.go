@@ -0,0 +1,232 @@
+package day2
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errNoInput is returned when a program executes an input instruction and the machine has no input source.
+var errNoInput = errors.New("program wants input but none is available")
+
+// paramCounts is the number of parameters each opcode takes.
+var paramCounts = map[int]int{
+	1:  3, // addition
+	2:  3, // multiplication
+	3:  1, // input
+	4:  1, // output
+	5:  2, // jump-if-true
+	6:  2, // jump-if-false
+	7:  3, // less than
+	8:  3, // equals
+	9:  1, // adjust relative base
+	99: 0, // finished
+}
+
+// effect describes what a single instruction did to the machine, which is
+// everything needed to undo or redo it.
+type effect struct {
+	ip       int // instruction pointer before the instruction ran
+	nextIP   int // instruction pointer after the instruction ran
+	addr     int // address written, or -1 if nothing was written
+	oldValue int
+	newValue int
+	base     int // relative base before the instruction ran
+	nextBase int // relative base after the instruction ran
+	halted   bool
+}
+
+// machine is an Intcode computer: a memory, an instruction pointer and a relative base.
+type machine struct {
+	memory       []int
+	ip           int
+	relativeBase int
+	halted       bool
+
+	// maxMemory lets writes grow memory up to this many cells. Zero keeps
+	// memory at the size of the program, as the day 2 puzzle expects.
+	maxMemory int
+
+	// input is called by opcode 3 and output by opcode 4.
+	input  func() (int, error)
+	output func(int) error
+}
+
+// extendedMemory is the memory limit for programs that use more than their own cells.
+const extendedMemory = 1 << 20
+
+// newMachine returns a machine running directly on memory, so the caller sees every write.
+func newMachine(memory []int) *machine {
+	return &machine{memory: memory}
+}
+
+func (m *machine) read(addr int) (int, error) {
+	if addr < 0 || addr >= len(m.memory) && addr >= m.maxMemory {
+		return 0, fmt.Errorf("address %d out of range at ip %d", addr, m.ip)
+	}
+	if addr >= len(m.memory) {
+		return 0, nil
+	}
+	return m.memory[addr], nil
+}
+
+func (m *machine) write(addr, val int) {
+	if addr >= len(m.memory) {
+		m.memory = append(m.memory, make([]int, addr+1-len(m.memory))...)
+	}
+	m.memory[addr] = val
+}
+
+// paramAddr returns the address parameter i of the current instruction refers to.
+// Position mode (0) uses the parameter as an address, immediate mode (1) the
+// parameter itself and relative mode (2) the parameter plus the relative base.
+func (m *machine) paramAddr(op, i int) (int, error) {
+	mode := op / 100
+	for j := 0; j < i; j++ {
+		mode /= 10
+	}
+	mode %= 10
+	raw := m.ip + 1 + i
+	if mode == 1 {
+		return raw, nil
+	}
+	val, err := m.read(raw)
+	if err != nil {
+		return 0, err
+	}
+	switch mode {
+	case 0:
+		return val, nil
+	case 2:
+		return m.relativeBase + val, nil
+	}
+	return 0, fmt.Errorf("unknown parameter mode %d at ip %d", mode, m.ip)
+}
+
+// step executes the instruction at the instruction pointer and returns its effect.
+func (m *machine) step() (effect, error) {
+	e := effect{ip: m.ip, nextIP: m.ip, addr: -1, base: m.relativeBase, nextBase: m.relativeBase}
+	if m.ip < 0 {
+		return e, fmt.Errorf("ip %d out of range", m.ip)
+	}
+	if m.halted || m.ip >= len(m.memory) {
+		m.halted = true
+		e.halted = true
+		return e, nil
+	}
+	op := m.memory[m.ip]
+	count, ok := paramCounts[op%100]
+	if !ok {
+		return e, fmt.Errorf("unknown opcode %d at ip %d", op, m.ip)
+	}
+	addrs := make([]int, count)
+	vals := make([]int, count)
+	for i := range addrs {
+		addr, err := m.paramAddr(op, i)
+		if err != nil {
+			return e, err
+		}
+		val, err := m.read(addr)
+		if err != nil {
+			return e, err
+		}
+		addrs[i] = addr
+		vals[i] = val
+	}
+	store := func(i, val int) {
+		e.addr = addrs[i]
+		e.oldValue = vals[i]
+		e.newValue = val
+	}
+	e.nextIP = m.ip + 1 + count
+	switch op % 100 {
+	case 1:
+		store(2, vals[0]+vals[1])
+	case 2:
+		store(2, vals[0]*vals[1])
+	case 3:
+		if m.input == nil {
+			return e, errNoInput
+		}
+		val, err := m.input()
+		if err != nil {
+			return e, err
+		}
+		store(0, val)
+	case 4:
+		if m.output == nil {
+			return e, fmt.Errorf("program produced output %d but has nowhere to send it", vals[0])
+		}
+		if err := m.output(vals[0]); err != nil {
+			return e, err
+		}
+	case 5:
+		if vals[0] != 0 {
+			e.nextIP = vals[1]
+		}
+	case 6:
+		if vals[0] == 0 {
+			e.nextIP = vals[1]
+		}
+	case 7:
+		if vals[0] < vals[1] {
+			store(2, 1)
+		} else {
+			store(2, 0)
+		}
+	case 8:
+		if vals[0] == vals[1] {
+			store(2, 1)
+		} else {
+			store(2, 0)
+		}
+	case 9:
+		e.nextBase = m.relativeBase + vals[0]
+	case 99:
+		e.nextIP = m.ip
+		e.halted = true
+	}
+	m.apply(e)
+	return e, nil
+}
+
+// apply replays a recorded effect onto the machine.
+func (m *machine) apply(e effect) {
+	if e.addr >= 0 {
+		m.write(e.addr, e.newValue)
+	}
+	m.ip = e.nextIP
+	m.relativeBase = e.nextBase
+	m.halted = e.halted
+}
+
+// undo reverts a recorded effect, leaving the machine as it was before the instruction ran.
+func (m *machine) undo(e effect) {
+	if e.addr >= 0 {
+		m.write(e.addr, e.oldValue)
+	}
+	m.ip = e.ip
+	m.relativeBase = e.base
+	m.halted = false
+}
+
+// run steps the machine until it halts or fails.
+func (m *machine) run() error {
+	for !m.halted {
+		if _, err := m.step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sliceInput returns an input source that yields inputs in order and then fails.
+func sliceInput(inputs []int) func() (int, error) {
+	return func() (int, error) {
+		if len(inputs) == 0 {
+			return 0, errNoInput
+		}
+		val := inputs[0]
+		inputs = inputs[1:]
+		return val, nil
+	}
+}
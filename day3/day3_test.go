@@ -0,0 +1,231 @@
+package day3
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cquon/aoc-2019/wire"
+)
+
+// The per-cell map solution below was the first day 3 solution. It stays here
+// to check and benchmark the segment intersection one against.
+
+func populateCoordinates(wireCoordinates map[int]map[int]struct{}, path wire.Path, costs wire.Costs) {
+	for cell := range path.Cells(costs) {
+		if wireCoordinates[cell.X] == nil {
+			wireCoordinates[cell.X] = make(map[int]struct{})
+		}
+		wireCoordinates[cell.X][cell.Y] = struct{}{}
+	}
+}
+
+func populateCoordinatesPt2(wireCoordinates map[int]map[int]int, path wire.Path, costs wire.Costs) {
+	for cell, totalSteps := range path.Cells(costs) {
+		if wireCoordinates[cell.X] == nil {
+			wireCoordinates[cell.X] = make(map[int]int)
+		}
+		if _, exists := wireCoordinates[cell.X][cell.Y]; !exists {
+			wireCoordinates[cell.X][cell.Y] = totalSteps
+		}
+	}
+}
+
+// getClosest is the cell the path shares with the first wire nearest the
+// central port by Manhattan distance.
+func getClosest(wire1Coordinates map[int]map[int]struct{}, path wire.Path, costs wire.Costs) (wire.Point, bool) {
+	var closest wire.Point
+	found := false
+	for cell := range path.Cells(costs) {
+		if _, exists := wire1Coordinates[cell.X][cell.Y]; !exists || cell == (wire.Point{}) {
+			continue
+		}
+		if !found || cell.Manhattan() < closest.Manhattan() {
+			closest, found = cell, true
+		}
+	}
+	return closest, found
+}
+
+// getCrossings lists the cells the path shares with the first wire, with the
+// steps each wire first takes to reach them.
+func getCrossings(wire1Coordinates map[int]map[int]int, path wire.Path, costs wire.Costs) []wire.Crossing {
+	var crossings []wire.Crossing
+	seen := make(map[wire.Point]struct{})
+	for cell, totalSteps := range path.Cells(costs) {
+		steps, exists := wire1Coordinates[cell.X][cell.Y]
+		if !exists || cell == (wire.Point{}) {
+			continue
+		}
+		if _, ok := seen[cell]; !ok {
+			seen[cell] = struct{}{}
+			crossings = append(crossings, wire.Crossing{Point: cell, StepsA: steps, StepsB: totalSteps})
+		}
+	}
+	return crossings
+}
+
+// mapDistance is the part 1 answer worked out on a map of every cell the first wire covers.
+func mapDistance(path1, path2 wire.Path) (int, error) {
+	coordinateMap := make(map[int]map[int]struct{}, len(path1))
+	populateCoordinates(coordinateMap, path1, nil)
+	closest, ok := getClosest(coordinateMap, path2, nil)
+	if !ok {
+		return 0, wire.ErrNoCrossing
+	}
+	return closest.Manhattan(), nil
+}
+
+// mapSteps is the part 2 answer worked out on a map of every cell the first wire covers.
+func mapSteps(path1, path2 wire.Path) (int, error) {
+	coordinateMapSteps := make(map[int]map[int]int, len(path1))
+	populateCoordinatesPt2(coordinateMapSteps, path1, nil)
+	fewest, ok := wire.Fewest(getCrossings(coordinateMapSteps, path2, nil))
+	if !ok {
+		return 0, wire.ErrNoCrossing
+	}
+	return fewest.Steps(), nil
+}
+
+// readInput reads the committed puzzle input.
+func readInput(tb testing.TB) string {
+	tb.Helper()
+	data, err := os.ReadFile("input.txt")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return string(data)
+}
+
+func readInputPaths(tb testing.TB) (wire.Path, wire.Path) {
+	tb.Helper()
+	path1, path2, err := puzzleWires(readInput(tb))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return path1, path2
+}
+
+func TestMapAndSegmentsAgree(t *testing.T) {
+	for _, test := range []struct {
+		input           string
+		distance, steps int
+	}{
+		{"R8,U5,L5,D3\nU7,R6,D4,L4\n", 6, 30},
+		{"R75,D30,R83,U83,L12,D49,R71,U7,L72\nU62,R66,U55,R34,D71,R55,D58,R83\n", 159, 610},
+		{"R98,U47,R26,D63,R33,U87,L62,D20,R33,U53,R51\nU98,R91,D20,R16,D67,R40,U7,R15,U6,R7\n", 135, 410},
+		{readInput(t), 225, 35194},
+	} {
+		path1, path2, err := puzzleWires(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		distance, err := mapDistance(path1, path2)
+		if err != nil || distance != test.distance {
+			t.Errorf("mapDistance = %d, %v, want %d", distance, err, test.distance)
+		}
+		steps, err := mapSteps(path1, path2)
+		if err != nil || steps != test.steps {
+			t.Errorf("mapSteps = %d, %v, want %d", steps, err, test.steps)
+		}
+		part1, err := crossedWires{}.Part1(test.input)
+		if err != nil || part1 != strconv.Itoa(test.distance) {
+			t.Errorf("Part1 = %s, %v, want %d", part1, err, test.distance)
+		}
+		part2, err := crossedWires{}.Part2(test.input)
+		if err != nil || part2 != strconv.Itoa(test.steps) {
+			t.Errorf("Part2 = %s, %v, want %d", part2, err, test.steps)
+		}
+	}
+}
+
+func TestParseWires(t *testing.T) {
+	input := "\nR8,U5,L5,D3\n  \nU7,R6,D4,L4\nr3,,U2\n"
+	paths, err := parseWires(input, wire.Parser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, path := range paths {
+		got = append(got, path.String())
+	}
+	if want := "R8,U5,L5,D3 U7,R6,D4,L4 R3,U2"; strings.Join(got, " ") != want {
+		t.Errorf("parseWires = %v, want %s", got, want)
+	}
+
+	_, err = parseWires(input, wire.Parser{Strict: true})
+	if want := `wire 3, move 2 (column 4), "": empty move`; err == nil || err.Error() != want {
+		t.Errorf("strict parseWires error = %v, want %s", err, want)
+	}
+	if err := (crossedWires{}).Validate(input); err == nil {
+		t.Errorf("Validate accepted an empty move")
+	}
+	if err := (crossedWires{}).Validate("R8,U5\n"); err == nil || err.Error() != "want 2 wires, got 1" {
+		t.Errorf("Validate of one wire = %v", err)
+	}
+}
+
+func TestNoCrossing(t *testing.T) {
+	input := "R8,U5\nL3,D2\n"
+	if _, err := (crossedWires{}).Part1(input); !errors.Is(err, wire.ErrNoCrossing) {
+		t.Errorf("Part1 error = %v, want %v", err, wire.ErrNoCrossing)
+	}
+	if _, err := (crossedWires{}).Part2(input); !errors.Is(err, wire.ErrNoCrossing) {
+		t.Errorf("Part2 error = %v, want %v", err, wire.ErrNoCrossing)
+	}
+}
+
+func TestWriteRoute(t *testing.T) {
+	paths, err := parseWires("R8,U5,L5,D3\nU7,R6,D4,L4\n", wire.Parser{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		penalty int
+		want    string
+	}{
+		{-1, "D1,R9,U7,L2\n19 steps, 4 moves, crosses wire 1 at 0 cells, crosses wire 2 at 0 cells\n"},
+		{0, "R7,U6\n13 steps, 2 moves, crosses wire 1 at 8 cells, crosses wire 2 at 0 cells\n"},
+	} {
+		route, err := wire.Router{Penalty: test.penalty}.Route(paths, wire.Point{X: 7, Y: 6})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		if err := writeRoute(&b, route, paths); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != test.want {
+			t.Errorf("route with penalty %d =\n%s\nwant\n%s", test.penalty, b.String(), test.want)
+		}
+	}
+}
+
+func BenchmarkMap(b *testing.B) {
+	path1, path2 := readInputPaths(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := mapDistance(path1, path2); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := mapSteps(path1, path2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSegments(b *testing.B) {
+	path1, path2 := readInputPaths(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cs := wire.Intersect(path1.Segments(), path2.Segments())
+		if _, ok := wire.Closest(cs); !ok {
+			b.Fatal(wire.ErrNoCrossing)
+		}
+		if _, ok := wire.Fewest(cs); !ok {
+			b.Fatal(wire.ErrNoCrossing)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package day3
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cquon/aoc-2019/wire"
+)
+
+// toolCommands are the wire tool's subcommands.
+var toolCommands = map[string]func(args []string) error{
+	"draw":   drawCommand,
+	"report": reportCommand,
+	"route":  routeCommand,
+	"signal": signalCommand,
+	"query":  queryCommand,
+}
+
+// Tool runs the wire tool subcommand named by args[0].
+func Tool(args []string) error {
+	if len(args) > 0 {
+		if command, ok := toolCommands[args[0]]; ok {
+			return command(args[1:])
+		}
+	}
+	var names []string
+	for name := range toolCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("usage: aoc wires %s [flags]", strings.Join(names, "|"))
+}
+
+// inputFlags adds the -input and -strict flags every subcommand reads its
+// wires with, returning a function that reads them once the flags are parsed.
+func inputFlags(flags *flag.FlagSet) func() ([]wire.Path, error) {
+	inputFile := flags.String("input", "day3/input.txt", "wire paths, one per line, - for stdin")
+	strict := flags.Bool("strict", false, "reject empty moves and zero or negative lengths")
+	return func() ([]wire.Path, error) {
+		var data []byte
+		var err error
+		if *inputFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(*inputFile)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return parseWires(string(data), wire.Parser{Strict: *strict})
+	}
+}
+
+// queryCommand answers questions about any number of wires, one per line of the input.
+func queryCommand(args []string) error {
+	flags := flag.NewFlagSet("wires query", flag.ContinueOnError)
+	readPaths := inputFlags(flags)
+	k := flags.Int("k", 0, "list the cells crossed by at least this many wires (at least 2)")
+	self := flags.Bool("self", false, "list the cells each wire crosses itself at")
+	costText := flags.String("costs", "", "steps to enter a cell by direction, such as UL=2,UR=2")
+	metricName := flags.String("metric", "manhattan", "distance metric for -top: "+strings.Join(wire.MetricNames(), ", ")+" (more can be added with wire.RegisterMetric)")
+	from := flags.String("from", "0,0", "reference point x,y for -top")
+	top := flags.Int("top", 0, "list the nearest n crossings of each pair of wires to the -from point")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	metric, err := wire.LookupMetric(*metricName)
+	if err != nil {
+		return err
+	}
+	var ref wire.Point
+	if _, err := fmt.Sscanf(*from, "%d,%d", &ref.X, &ref.Y); err != nil {
+		return fmt.Errorf("-from %q must look like x,y", *from)
+	}
+	costs, err := wire.ParseCosts(*costText)
+	if err != nil {
+		return err
+	}
+	paths, err := readPaths()
+	if err != nil {
+		return err
+	}
+
+	pairs := wire.Pairwise(paths, costs)
+	for _, pair := range pairs {
+		closest, ok := wire.Closest(pair.Crossings)
+		if !ok {
+			fmt.Printf("Wires %d and %d: never cross\n", pair.A+1, pair.B+1)
+			continue
+		}
+		fewest, _ := wire.Fewest(pair.Crossings)
+		fmt.Printf("Wires %d and %d: %d crossings, closest %v at distance %d, fewest steps %d at %v\n",
+			pair.A+1, pair.B+1, len(pair.Crossings), closest.Point, closest.Point.Manhattan(), fewest.Steps(), fewest.Point)
+		if *top > 0 {
+			for i, r := range wire.Rank(pair.Crossings, metric, ref, *top) {
+				fmt.Printf("  %d. %v at %s distance %.6g, %d steps\n", i+1, r.Point, strings.ToLower(*metricName), r.Distance, r.Steps())
+			}
+		}
+	}
+	if *k > 0 {
+		if *k < 2 {
+			return fmt.Errorf("-k must be at least 2")
+		}
+		multi := wire.CrossedBy(pairs, *k)
+		fmt.Printf("Cells crossed by at least %d wires: %d\n", *k, len(multi))
+		for _, mc := range multi {
+			wires := make([]string, len(mc.Wires))
+			for i, w := range mc.Wires {
+				wires[i] = fmt.Sprintf("%d (%d steps)", w+1, mc.Steps[i])
+			}
+			fmt.Printf("  %v: wires %s\n", mc.Point, strings.Join(wires, ", "))
+		}
+	}
+	if *self {
+		for i, path := range paths {
+			crossings := wire.SelfCrossings(path, costs)
+			fmt.Printf("Wire %d crosses itself at %d cells\n", i+1, len(crossings))
+			for _, sc := range crossings {
+				fmt.Printf("  %v at steps %v\n", sc.Point, sc.Steps)
+			}
+		}
+	}
+	return nil
+}
+
+// parseCrop parses a crop rectangle given as "x0,y0,x1,y1".
+func parseCrop(text string) (wire.View, error) {
+	var v wire.View
+	if _, err := fmt.Sscanf(text, "%d,%d,%d,%d", &v.Min.X, &v.Min.Y, &v.Max.X, &v.Max.Y); err != nil {
+		return v, fmt.Errorf("crop %q must look like x0,y0,x1,y1", text)
+	}
+	v.Min, v.Max = wire.Point{X: min(v.Min.X, v.Max.X), Y: min(v.Min.Y, v.Max.Y)}, wire.Point{X: max(v.Min.X, v.Max.X), Y: max(v.Min.Y, v.Max.Y)}
+	return v, nil
+}
+
+// drawCommand draws wires the way the puzzle does, and optionally as a PNG
+// with the closest and fewest step crossings highlighted.
+func drawCommand(args []string) error {
+	flags := flag.NewFlagSet("wires draw", flag.ContinueOnError)
+	readPaths := inputFlags(flags)
+	crop := flags.String("crop", "", "only draw the cells from x0,y0 to x1,y1")
+	width := flags.Int("width", 120, "scale the drawing down to at most this many columns, 0 for no limit")
+	height := flags.Int("height", 60, "scale the drawing down to at most this many rows, 0 for no limit")
+	pngFile := flags.String("png", "", "also save the wires as a PNG")
+	size := flags.Int("size", 1024, "scale the PNG down to at most this many blocks a side, 0 for no limit")
+	zoom := flags.Int("zoom", 1, "PNG pixels per block")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	paths, err := readPaths()
+	if err != nil {
+		return err
+	}
+	canvas := wire.NewCanvas(paths)
+	view := canvas.View()
+	if *crop != "" {
+		if view, err = parseCrop(*crop); err != nil {
+			return err
+		}
+	}
+	fmt.Print(canvas.ASCII(view.Fit(*width, *height)))
+	if *pngFile == "" {
+		return nil
+	}
+
+	var crossings []wire.Crossing
+	for _, pair := range wire.Pairwise(paths, nil) {
+		crossings = append(crossings, pair.Crossings...)
+	}
+	var marks []wire.Mark
+	if closest, ok := wire.Closest(crossings); ok {
+		marks = append(marks, wire.Mark{Point: closest.Point, Color: color.RGBA{0xff, 0xff, 0x00, 0xff}, Radius: 4})
+	}
+	if fewest, ok := wire.Fewest(crossings); ok {
+		marks = append(marks, wire.Mark{Point: fewest.Point, Color: color.RGBA{0xff, 0x00, 0xff, 0xff}, Radius: 2})
+	}
+	file, err := os.Create(*pngFile)
+	if err != nil {
+		return err
+	}
+	if err := canvas.WritePNG(file, view.Fit(*size, *size), *zoom, marks...); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// reportCommand lists every crossing of two of the wires.
+func reportCommand(args []string) error {
+	flags := flag.NewFlagSet("wires report", flag.ContinueOnError)
+	readPaths := inputFlags(flags)
+	a := flags.Int("a", 1, "first wire to report on")
+	b := flags.Int("b", 2, "second wire to report on")
+	sortBy := flags.String("sort", "distance", "column to sort by: x, y, distance, steps_a, steps_b or steps")
+	desc := flags.Bool("desc", false, "sort largest first")
+	format := flags.String("format", "table", "output format: table, csv or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	paths, err := readPaths()
+	if err != nil {
+		return err
+	}
+	if *a < 1 || *a > len(paths) || *b < 1 || *b > len(paths) || *a == *b {
+		return fmt.Errorf("-a and -b must be two different wires from 1 to %d", len(paths))
+	}
+	report := wire.NewReport(wire.Intersect(paths[*a-1].Segments(), paths[*b-1].Segments()))
+	if err := report.SortBy(*sortBy, *desc); err != nil {
+		return err
+	}
+	return report.Write(os.Stdout, *format)
+}
+
+// signalCommand follows signals down two of the wires, showing when each
+// crossing is reached by both under a delay rule.
+func signalCommand(args []string) error {
+	flags := flag.NewFlagSet("wires signal", flag.ContinueOnError)
+	readPaths := inputFlags(flags)
+	a := flags.Int("a", 1, "first wire")
+	b := flags.Int("b", 2, "second wire")
+	ruleName := flags.String("rule", "first", "delay to a cell visited more than once: first, last or loopfree")
+	costText := flags.String("costs", "", "steps to enter a cell by direction, such as UL=2,UR=2")
+	tick := flags.Int("t", -1, "only list the crossings both signals have reached by this tick")
+	loops := flags.Bool("loops", false, "list every visit to the cells each wire loops back through")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	rule, err := wire.ParseDelayRule(*ruleName)
+	if err != nil {
+		return err
+	}
+	costs, err := wire.ParseCosts(*costText)
+	if err != nil {
+		return err
+	}
+	paths, err := readPaths()
+	if err != nil {
+		return err
+	}
+	if *a < 1 || *a > len(paths) || *b < 1 || *b > len(paths) || *a == *b {
+		return fmt.Errorf("-a and -b must be two different wires from 1 to %d", len(paths))
+	}
+
+	if *loops {
+		for _, n := range []int{*a, *b} {
+			var looped []wire.Point
+			visits := wire.Visits(paths[n-1], costs)
+			for p, steps := range visits {
+				if len(steps) > 1 {
+					looped = append(looped, p)
+				}
+			}
+			sort.Slice(looped, func(i, j int) bool {
+				return visits[looped[i]][0] < visits[looped[j]][0]
+			})
+			fmt.Printf("Wire %d visits %d cells, %d of them more than once\n", n, len(visits), len(looped))
+			for _, p := range looped {
+				fmt.Printf("  %v at steps %v\n", p, visits[p])
+			}
+		}
+	}
+
+	crossings := wire.DelayCrossings(wire.Delays(paths[*a-1], costs, rule), wire.Delays(paths[*b-1], costs, rule))
+	timeline := wire.Timeline(crossings)
+	if *tick >= 0 {
+		timeline = wire.ReachedBy(crossings, *tick)
+		fmt.Printf("Crossings reached by both signals by tick %d (delay rule %s): %d of %d\n", *tick, rule, len(timeline), len(crossings))
+	} else {
+		fmt.Printf("Crossings in the order both signals reach them (delay rule %s): %d\n", rule, len(crossings))
+	}
+	for _, c := range timeline {
+		fmt.Printf("  tick %d: %v, wire %d at %d steps, wire %d at %d steps, %d combined\n", c.Reached(), c.Point, *a, c.StepsA, *b, c.StepsB, c.Steps())
+	}
+	if fewest, ok := wire.Fewest(crossings); ok {
+		fmt.Printf("Fewest combined steps: %d at %v\n", fewest.Steps(), fewest.Point)
+	}
+	return nil
+}
+
+// routeCommand plans a new wire from the central port to a target around the existing wires.
+func routeCommand(args []string) error {
+	flags := flag.NewFlagSet("wires route", flag.ContinueOnError)
+	readPaths := inputFlags(flags)
+	to := flags.String("to", "", "target cell x,y")
+	penalty := flags.Int("penalty", -1, "extra steps charged for each cell an existing wire covers, -1 to avoid them")
+	maxNodes := flags.Int("max", 0, "give up after searching this many cells, 0 for the default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	var target wire.Point
+	if _, err := fmt.Sscanf(*to, "%d,%d", &target.X, &target.Y); err != nil {
+		return fmt.Errorf("-to %q must look like x,y", *to)
+	}
+	paths, err := readPaths()
+	if err != nil {
+		return err
+	}
+
+	router := wire.Router{Penalty: *penalty, MaxNodes: *maxNodes}
+	route, err := router.Route(paths, target)
+	if err != nil {
+		return err
+	}
+	return writeRoute(os.Stdout, route, paths)
+}
+
+// writeRoute writes route, then how long it is and how often it crosses each existing wire.
+func writeRoute(w io.Writer, route wire.Path, existing []wire.Path) error {
+	steps := 0
+	for _, m := range route {
+		steps += m.Length
+	}
+	fmt.Fprintln(w, route)
+	fmt.Fprintf(w, "%d steps, %d moves", steps, len(route))
+	for i, path := range existing {
+		fmt.Fprintf(w, ", crosses wire %d at %d cells", i+1, len(wire.Intersect(route.Segments(), path.Segments())))
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
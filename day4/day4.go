@@ -1,8 +1,11 @@
-package main
+package day4
 
 import (
 	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/cquon/aoc-2019/solution"
 )
 
 /*
@@ -75,19 +78,64 @@ func isValidNumPt2(number string) bool {
 }
 
 
-func main() {
-	lowerBound := 273025
-	upperBound := 767253
+// parseRange reads a puzzle input of the form "273025-767253".
+func parseRange(input string) (int, int, error) {
+	lower, upper, found := strings.Cut(strings.TrimSpace(input), "-")
+	if !found {
+		return 0, 0, fmt.Errorf("range %q must look like 273025-767253", input)
+	}
+	lowerBound, err := strconv.Atoi(lower)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid lower bound %q", lower)
+	}
+	upperBound, err := strconv.Atoi(upper)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid upper bound %q", upper)
+	}
+	return lowerBound, upperBound, nil
+}
+
+// countValid counts the numbers within the input range that isValid accepts.
+func countValid(input string, isValid func(string) bool) (string, error) {
+	lowerBound, upperBound, err := parseRange(input)
+	if err != nil {
+		return "", err
+	}
 	count := 0
-	countPt2 := 0
 	for i:=lowerBound; i<=upperBound; i++ {
-		if isValidNum(strconv.Itoa(i)) {
+		if isValid(strconv.Itoa(i)) {
 			count++
 		}
-		if isValidNumPt2(strconv.Itoa(i)) {
-			countPt2++
-		}
 	}
-	fmt.Printf("Part 1: Num Passwords: %d\n", count)
-	fmt.Printf("Part 2: Num Passwords: %d\n", countPt2)
+	return strconv.Itoa(count), nil
+}
+
+// secureContainer is the day 4 solution.
+type secureContainer struct{}
+
+func (secureContainer) Part1(input string) (string, error) {
+	return countValid(input, isValidNum)
+}
+
+func (secureContainer) Part2(input string) (string, error) {
+	return countValid(input, isValidNumPt2)
+}
+
+func init() {
+	solution.Register(4, secureContainer{})
+}
+
+// Validate checks the input is a range of six digit numbers.
+func (secureContainer) Validate(input string) error {
+	lowerBound, upperBound, err := parseRange(input)
+	if err != nil {
+		return err
+	}
+	if lowerBound < 100000 || upperBound > 999999 {
+		return fmt.Errorf("range %d-%d is not six digit numbers", lowerBound, upperBound)
+	}
+	if lowerBound > upperBound {
+		return fmt.Errorf("range %d-%d is backwards", lowerBound, upperBound)
+	}
+	return nil
 }
@@ -0,0 +1,160 @@
+package fuel
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// Delta compares one module, or the whole spacecraft, between two manifests.
+// Masses and fuel count every copy of the module.
+type Delta struct {
+	Module   string `json:"module"`
+	Status   string `json:"status"` // "added", "removed", "changed" or "unchanged"
+	OldMass  int    `json:"old_mass"`
+	NewMass  int    `json:"new_mass"`
+	OldFuel  int    `json:"old_fuel"`
+	NewFuel  int    `json:"new_fuel"`
+	OldTotal int    `json:"old_total"`
+	NewTotal int    `json:"new_total"`
+}
+
+// FuelDelta is the change in base fuel.
+func (d Delta) FuelDelta() int {
+	return d.NewFuel - d.OldFuel
+}
+
+// TotalDelta is the change in fuel including fuel for fuel.
+func (d Delta) TotalDelta() int {
+	return d.NewTotal - d.OldTotal
+}
+
+// add adds other's masses and fuel to d's, failing rather than wrapping if any of them overflows.
+func (d *Delta) add(other Delta) error {
+	if !fits(d.OldMass, other.OldMass) || !fits(d.NewMass, other.NewMass) ||
+		!fits(d.OldFuel, other.OldFuel) || !fits(d.NewFuel, other.NewFuel) ||
+		!fits(d.OldTotal, other.OldTotal) || !fits(d.NewTotal, other.NewTotal) {
+		return fmt.Errorf("%s: totals overflow an int", other.Module)
+	}
+	d.OldMass += other.OldMass
+	d.NewMass += other.NewMass
+	d.OldFuel += other.OldFuel
+	d.NewFuel += other.NewFuel
+	d.OldTotal += other.OldTotal
+	d.NewTotal += other.NewTotal
+	return nil
+}
+
+// Diff is the change in fuel between two versions of a manifest.
+type Diff struct {
+	Modules []Delta `json:"modules"`
+	Total   Delta   `json:"total"`
+}
+
+// partFuel is the mass and fuel of every copy of a part.
+type partFuel struct {
+	mass, fuel, total int
+}
+
+func manifestFuel(s *Subsystem, model Model) (map[string]partFuel, []string, error) {
+	parts := make(map[string]partFuel)
+	var order []string
+	var err error
+	s.Walk(func(path string, p *Part, _ []string) {
+		if err != nil {
+			return
+		}
+		m, breakdownErr := BreakdownWith(model, p.Line, p.Mass)
+		if breakdownErr != nil {
+			err = fmt.Errorf("%s: %v", path, breakdownErr)
+			return
+		}
+		mass, fuel, total, copiesErr := copies(p.Quantity, m)
+		if copiesErr != nil {
+			err = fmt.Errorf("%s: %v", path, copiesErr)
+			return
+		}
+		parts[path] = partFuel{mass, fuel, total}
+		order = append(order, path)
+	})
+	return parts, order, err
+}
+
+// Compare matches the modules of two manifests by path, or by line number for
+// flat mass lists, and works out how the fuel of each changed under model.
+// Modules are listed in the order of the new manifest, then removed ones.
+func Compare(oldManifest, newManifest *Subsystem, model Model) (*Diff, error) {
+	oldParts, oldOrder, err := manifestFuel(oldManifest, model)
+	if err != nil {
+		return nil, err
+	}
+	newParts, newOrder, err := manifestFuel(newManifest, model)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Diff{Total: Delta{Module: "total"}}
+	add := func(delta Delta) error {
+		d.Modules = append(d.Modules, delta)
+		return d.Total.add(delta)
+	}
+	for _, path := range newOrder {
+		n := newParts[path]
+		delta := Delta{Module: path, Status: "added", NewMass: n.mass, NewFuel: n.fuel, NewTotal: n.total}
+		if o, ok := oldParts[path]; ok {
+			delta.OldMass, delta.OldFuel, delta.OldTotal = o.mass, o.fuel, o.total
+			delta.Status = "unchanged"
+			if o != n {
+				delta.Status = "changed"
+			}
+		}
+		if err := add(delta); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range oldOrder {
+		if _, ok := newParts[path]; ok {
+			continue
+		}
+		o := oldParts[path]
+		if err := add(Delta{Module: path, Status: "removed", OldMass: o.mass, OldFuel: o.fuel, OldTotal: o.total}); err != nil {
+			return nil, err
+		}
+	}
+	d.Total.Status = "unchanged"
+	if d.Total.OldMass != d.Total.NewMass || d.Total.OldTotal != d.Total.NewTotal || d.Total.OldFuel != d.Total.NewFuel {
+		d.Total.Status = "changed"
+	}
+	return d, nil
+}
+
+// SortByImpact orders the modules by how much their total fuel changed, biggest change first.
+func (d *Diff) SortByImpact() {
+	sort.SliceStable(d.Modules, func(i, j int) bool {
+		return abs(d.Modules[i].TotalDelta()) > abs(d.Modules[j].TotalDelta())
+	})
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// WriteTable writes the modules that changed, and the totals, as an aligned text table.
+func (d *Diff) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Module\tStatus\tMass\tFuel\tΔ fuel\tTotal fuel\tΔ total fuel")
+	for _, delta := range append(d.Modules, d.Total) {
+		if delta.Status == "unchanged" && delta.Module != "total" {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d -> %d\t%d -> %d\t%+d\t%d -> %d\t%+d\n",
+			delta.Module, delta.Status, delta.OldMass, delta.NewMass,
+			delta.OldFuel, delta.NewFuel, delta.FuelDelta(),
+			delta.OldTotal, delta.NewTotal, delta.TotalDelta())
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,96 @@
+package fuel
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustReadManifest(t *testing.T, text string) *Subsystem {
+	t.Helper()
+	s, err := ReadManifest(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestCompare(t *testing.T) {
+	oldManifest := mustReadManifest(t, `{"name": "s", "modules": [
+		{"name": "cabin", "mass": 1969},
+		{"name": "engine", "mass": 12000, "quantity": 2},
+		{"name": "antenna", "mass": 14}
+	]}`)
+	newManifest := mustReadManifest(t, `{"name": "s", "modules": [
+		{"name": "engine", "mass": 12000, "quantity": 3},
+		{"name": "cabin", "mass": 1969},
+		{"name": "probe", "mass": 100756}
+	]}`)
+	d, err := Compare(oldManifest, newManifest, Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Delta{
+		{Module: "engine", Status: "changed", OldMass: 24000, NewMass: 36000, OldFuel: 7996, NewFuel: 11994, OldTotal: 11950, NewTotal: 17925},
+		{Module: "cabin", Status: "unchanged", OldMass: 1969, NewMass: 1969, OldFuel: 654, NewFuel: 654, OldTotal: 966, NewTotal: 966},
+		{Module: "probe", Status: "added", NewMass: 100756, NewFuel: 33583, NewTotal: 50346},
+		{Module: "antenna", Status: "removed", OldMass: 14, OldFuel: 2, OldTotal: 2},
+	}
+	if len(d.Modules) != len(want) {
+		t.Fatalf("Compare gave %+v, want %+v", d.Modules, want)
+	}
+	for i := range want {
+		if d.Modules[i] != want[i] {
+			t.Errorf("module %d = %+v, want %+v", i, d.Modules[i], want[i])
+		}
+	}
+	total := Delta{Module: "total", Status: "changed", OldMass: 25983, NewMass: 138725, OldFuel: 8652, NewFuel: 46231, OldTotal: 12918, NewTotal: 69237}
+	if d.Total != total {
+		t.Errorf("total = %+v, want %+v", d.Total, total)
+	}
+	if d.Total.TotalDelta() != 69237-12918 || d.Modules[3].FuelDelta() != -2 {
+		t.Errorf("deltas %d and %d", d.Total.TotalDelta(), d.Modules[3].FuelDelta())
+	}
+
+	d.SortByImpact()
+	var b strings.Builder
+	if err := d.WriteTable(&b); err != nil {
+		t.Fatal(err)
+	}
+	wantTable := `Module   Status   Mass             Fuel           Δ fuel  Total fuel      Δ total fuel
+probe    added    0 -> 100756      0 -> 33583     +33583  0 -> 50346      +50346
+engine   changed  24000 -> 36000   7996 -> 11994  +3998   11950 -> 17925  +5975
+antenna  removed  14 -> 0          2 -> 0         -2      2 -> 0          -2
+total    changed  25983 -> 138725  8652 -> 46231  +37579  12918 -> 69237  +56319
+`
+	if b.String() != wantTable {
+		t.Errorf("table:\n%s\nwant:\n%s", b.String(), wantTable)
+	}
+}
+
+func TestCompareFlat(t *testing.T) {
+	d, err := Compare(mustReadManifest(t, "12\n14\n"), mustReadManifest(t, "12\n1969\n"), Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Modules) != 2 || d.Modules[0].Status != "unchanged" || d.Modules[1].Module != "#2" || d.Modules[1].Status != "changed" {
+		t.Errorf("Compare of flat lists = %+v", d.Modules)
+	}
+	same, err := Compare(mustReadManifest(t, "12\n"), mustReadManifest(t, "12\n"), Default)
+	if err != nil || same.Total.Status != "unchanged" {
+		t.Errorf("Compare of identical lists = %+v, %v", same, err)
+	}
+}
+
+func TestCompareOverflow(t *testing.T) {
+	huge := &Subsystem{Name: "s", Modules: []*Part{{Name: "a", Mass: 1 << 40, Quantity: MaxQuantity}}}
+	if _, err := Compare(&Subsystem{Name: "s"}, huge, Default); err == nil || !strings.Contains(err.Error(), "overflow") {
+		t.Errorf("Compare with a product overflow = %v", err)
+	}
+	big := &Subsystem{Name: "s", Modules: []*Part{
+		{Name: "a", Mass: 1 << 62, Quantity: 1},
+		{Name: "b", Mass: 1 << 62, Quantity: 1},
+	}}
+	if _, err := Compare(&Subsystem{Name: "s"}, big, Default); err == nil || !strings.Contains(err.Error(), "b: totals overflow") {
+		t.Errorf("Compare with a sum overflow = %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package fuel
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// SortByTotal orders the modules by the fuel they need, largest first, so the
+// modules that dominate the budget come first.
+func (r *Report) SortByTotal() {
+	sort.SliceStable(r.Modules, func(i, j int) bool {
+		return r.Modules[i].Total > r.Modules[j].Total
+	})
+}
+
+func joinInts(values []int, sep string) string {
+	fields := make([]string, len(values))
+	for i, val := range values {
+		fields[i] = strconv.Itoa(val)
+	}
+	return strings.Join(fields, sep)
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes one row per module followed by a row of totals. The fuel for
+// fuel iterations are joined with "+" in a single column.
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"module", "mass", "fuel", "fuel_for_fuel", "total", "share"})
+	for _, m := range r.Modules {
+		writer.Write([]string{
+			strconv.Itoa(m.Number),
+			strconv.Itoa(m.Mass),
+			strconv.Itoa(m.Fuel),
+			joinInts(m.FuelForFuel, "+"),
+			strconv.Itoa(m.Total),
+			strconv.FormatFloat(r.Share(m), 'f', 6, 64),
+		})
+	}
+	writer.Write([]string{"total", strconv.Itoa(r.Mass), strconv.Itoa(r.Fuel), "", strconv.Itoa(r.Total), "1"})
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteTable writes the report as an aligned text table.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Module\tMass\tFuel\tFuel for fuel\tTotal\tShare\t")
+	for _, m := range r.Modules {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%d\t%.2f%%\t\n", m.Number, m.Mass, m.Fuel, joinInts(m.FuelForFuel, " + "), m.Total, 100*r.Share(m))
+	}
+	fmt.Fprintf(tw, "Total\t%d\t%d\t\t%d\t100.00%%\t\n", r.Mass, r.Fuel, r.Total)
+	return tw.Flush()
+}
+
+// Write writes the report in format: "table", "csv" or "json".
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case "table":
+		return r.WriteTable(w)
+	case "csv":
+		return r.WriteCSV(w)
+	case "json":
+		return r.WriteJSON(w)
+	}
+	return fmt.Errorf("unknown format %q, want table, csv or json", format)
+}
@@ -0,0 +1,70 @@
+package fuel
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sortedReport() *Report {
+	r := NewReport([]int{12, 1969, 14})
+	r.SortByTotal()
+	return r
+}
+
+func TestWriteTable(t *testing.T) {
+	var b strings.Builder
+	if err := sortedReport().Write(&b, "table"); err != nil {
+		t.Fatal(err)
+	}
+	want := `  Module  Mass  Fuel      Fuel for fuel  Total    Share
+       2  1969   654  216 + 70 + 21 + 5    966   99.59%
+       1    12     2                         2    0.21%
+       3    14     2                         2    0.21%
+   Total  1995   658                       970  100.00%
+`
+	if b.String() != want {
+		t.Errorf("table:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b strings.Builder
+	if err := sortedReport().Write(&b, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	want := `module,mass,fuel,fuel_for_fuel,total,share
+2,1969,654,216+70+21+5,966,0.995876
+1,12,2,,2,0.002062
+3,14,2,,2,0.002062
+total,1995,658,,970,1
+`
+	if b.String() != want {
+		t.Errorf("csv:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	r := sortedReport()
+	var b strings.Builder
+	if err := r.Write(&b, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, r) {
+		t.Errorf("JSON decodes to %+v, want %+v", decoded, *r)
+	}
+	if !strings.Contains(b.String(), `"fuel_for_fuel": [`+"\n        216,") {
+		t.Errorf("JSON isn't indented:\n%s", b.String())
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := sortedReport().Write(&strings.Builder{}, "xml"); err == nil {
+		t.Error("want an error for an unknown format")
+	}
+}
@@ -0,0 +1,109 @@
+// Package fuel calculates the fuel needed to launch spacecraft modules with the
+// rocket equation from day 1, and reports how each module's fuel is made up.
+package fuel
+
+import (
+	"fmt"
+)
+
+// The puzzle's formula, shared by Default and the int64 and big.Int versions
+// of Required so the compiler can divide by a constant.
+const (
+	defaultDivisor    = 3
+	defaultSubtrahend = 2
+)
+
+// Required is the fuel needed to launch mass: divide by three, round down and
+// subtract 2. Small masses give zero or negative fuel.
+func Required(mass int) int {
+	return mass/defaultDivisor - defaultSubtrahend
+}
+
+// RequiredRecursive is the fuel needed to launch mass and all the fuel added
+// for it, treating any negative fuel requirement as zero.
+func RequiredRecursive(mass int) int {
+	return int(RequiredRecursive64(int64(mass)))
+}
+
+// Module is the fuel breakdown of a single module.
+type Module struct {
+	Number      int   `json:"number"` // position of the module in the input, from 1
+	Mass        int   `json:"mass"`
+	Fuel        int   `json:"fuel"`          // fuel for the module's mass alone, Required(Mass)
+	FuelForFuel []int `json:"fuel_for_fuel"` // each further amount of fuel needed for the previous one
+	Total       int   `json:"total"`         // all the fuel needed, RequiredRecursive(Mass)
+}
+
+// Breakdown returns the fuel breakdown of the module of mass at position number.
+func Breakdown(number, mass int) Module {
+	// The default model always converges.
+	m, _ := BreakdownWith(Default, number, mass)
+	return m
+}
+
+// BreakdownWith returns the fuel breakdown of a module under model.
+func BreakdownWith(model Model, number, mass int) (Module, error) {
+	base, extras, err := fuelForFuel(model, mass)
+	if err != nil {
+		return Module{}, err
+	}
+	m := Module{Number: number, Mass: mass, Fuel: base, FuelForFuel: extras}
+	if base > 0 {
+		m.Total = base
+	}
+	for _, extra := range extras {
+		m.Total += extra
+	}
+	return m, nil
+}
+
+// Report is the fuel breakdown of every module of a spacecraft and their totals.
+type Report struct {
+	Modules []Module `json:"modules"`
+	Mass    int      `json:"mass"`
+	Fuel    int      `json:"fuel"`  // the day 1 part 1 answer
+	Total   int      `json:"total"` // the day 1 part 2 answer
+}
+
+// NewReport breaks down the fuel of modules with the given masses. Its totals
+// wrap if they overflow an int; NewReportWith reports that instead.
+func NewReport(masses []int) *Report {
+	r := &Report{}
+	for i, mass := range masses {
+		r.Add(Breakdown(i+1, mass))
+	}
+	return r
+}
+
+// NewReportWith breaks down the fuel of modules with the given masses under
+// model, failing if the totals overflow an int.
+func NewReportWith(model Model, masses []int) (*Report, error) {
+	r := &Report{}
+	for i, mass := range masses {
+		m, err := BreakdownWith(model, i+1, mass)
+		if err != nil {
+			return nil, fmt.Errorf("module %d: %v", i+1, err)
+		}
+		if !fits(r.Mass, m.Mass) || !fits(r.Fuel, m.Fuel) || !fits(r.Total, m.Total) {
+			return nil, fmt.Errorf("module %d: totals overflow an int", i+1)
+		}
+		r.Add(m)
+	}
+	return r, nil
+}
+
+// Add adds a module to the report and its totals.
+func (r *Report) Add(m Module) {
+	r.Modules = append(r.Modules, m)
+	r.Mass += m.Mass
+	r.Fuel += m.Fuel
+	r.Total += m.Total
+}
+
+// Share is the fraction of the report's total fuel needed by m.
+func (r *Report) Share(m Module) float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(m.Total) / float64(r.Total)
+}
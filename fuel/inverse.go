@@ -0,0 +1,101 @@
+package fuel
+
+import (
+	"fmt"
+	"math"
+)
+
+// FuelFunc is a fuel calculation that never decreases as the mass grows, like
+// Required and RequiredRecursive. The inverse solvers rely on that to binary search.
+type FuelFunc func(mass int) int
+
+// upperMass finds a mass needing more than fuel, doubling from 1.
+func upperMass(f FuelFunc, fuel int) (int, error) {
+	hi := 1
+	for f(hi) <= fuel {
+		if hi > math.MaxInt/2 {
+			return 0, fmt.Errorf("no mass needs more than %d fuel", fuel)
+		}
+		hi *= 2
+	}
+	return hi, nil
+}
+
+// MaxMass is the largest mass whose fuel fits in budget.
+func MaxMass(f FuelFunc, budget int) (int, error) {
+	if budget < 0 {
+		return 0, fmt.Errorf("budget must be at least 0, got %d", budget)
+	}
+	if f(0) > budget {
+		return 0, fmt.Errorf("a budget of %d fuel can't launch any mass", budget)
+	}
+	hi, err := upperMass(f, budget)
+	if err != nil {
+		return 0, err
+	}
+	// f(lo) <= budget < f(hi)
+	lo := 0
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if f(mid) <= budget {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
+// MinMass is the smallest mass that needs exactly fuel. Not every amount of
+// fuel is needed by some mass, in which case it reports false.
+func MinMass(f FuelFunc, fuel int) (int, bool) {
+	if f(0) >= fuel {
+		return 0, f(0) == fuel
+	}
+	hi, err := upperMass(f, fuel-1)
+	if err != nil {
+		return 0, false
+	}
+	// f(lo) < fuel <= f(hi)
+	lo := 0
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if f(mid) < fuel {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, f(hi) == fuel
+}
+
+// fuelNeeded is f(mass) with negative fuel counted as none, as when adding up modules.
+func fuelNeeded(f FuelFunc, mass int) int {
+	return max(f(mass), 0)
+}
+
+// MaxMasses shares budget between n modules, making them as heavy as possible:
+// each module gets the largest mass that fits an even share of the budget, then
+// the leftover fuel makes the first modules heavier one at a time.
+func MaxMasses(f FuelFunc, budget, n int) ([]int, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("need at least one module, got %d", n)
+	}
+	if budget < 0 {
+		return nil, fmt.Errorf("budget must be at least 0, got %d", budget)
+	}
+	share, err := MaxMass(f, budget/n)
+	if err != nil {
+		return nil, err
+	}
+	masses := make([]int, n)
+	leftover := budget - n*fuelNeeded(f, share)
+	for i := range masses {
+		masses[i], err = MaxMass(f, fuelNeeded(f, share)+leftover)
+		if err != nil {
+			return nil, err
+		}
+		leftover -= fuelNeeded(f, masses[i]) - fuelNeeded(f, share)
+	}
+	return masses, nil
+}
@@ -0,0 +1,97 @@
+package fuel
+
+import (
+	"math/rand"
+	"testing"
+)
+
+var fuelFuncs = map[string]FuelFunc{
+	"Required":          Required,
+	"RequiredRecursive": RequiredRecursive,
+}
+
+func TestMaxMass(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for name, f := range fuelFuncs {
+		budgets := []int{0, 1, 2, 100, 654, 966, 50346}
+		for i := 0; i < 2000; i++ {
+			budgets = append(budgets, rng.Intn(1<<40))
+		}
+		for _, budget := range budgets {
+			m, err := MaxMass(f, budget)
+			if err != nil {
+				t.Fatalf("%s: MaxMass(%d): %v", name, budget, err)
+			}
+			if f(m) > budget || f(m+1) <= budget {
+				t.Fatalf("%s: MaxMass(%d) = %d, but f(%d) = %d and f(%d) = %d", name, budget, m, m, f(m), m+1, f(m+1))
+			}
+		}
+	}
+}
+
+func TestMinMass(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for name, f := range fuelFuncs {
+		fuels := []int{-2, -1, 0, 1, 2, 654, 966, 50346}
+		for i := 0; i < 2000; i++ {
+			fuels = append(fuels, rng.Intn(1<<40))
+		}
+		for _, fuel := range fuels {
+			m, ok := MinMass(f, fuel)
+			if ok && f(m) != fuel {
+				t.Fatalf("%s: MinMass(%d) = %d, but f(%d) = %d", name, fuel, m, m, f(m))
+			}
+			if ok && m > 0 && f(m-1) >= fuel {
+				t.Fatalf("%s: MinMass(%d) = %d is not the smallest, f(%d) = %d", name, fuel, m, m-1, f(m-1))
+			}
+			if !ok && m > 0 && (f(m-1) >= fuel || f(m) <= fuel) {
+				t.Fatalf("%s: MinMass(%d) says no mass needs it, but f(%d) = %d and f(%d) = %d", name, fuel, m-1, f(m-1), m, f(m))
+			}
+		}
+	}
+}
+
+func TestMinMassExhaustive(t *testing.T) {
+	for name, f := range fuelFuncs {
+		first := make(map[int]int)
+		for mass := 20000; mass >= 0; mass-- {
+			first[f(mass)] = mass
+		}
+		for fuel := 0; fuel < f(20000); fuel++ {
+			want, exists := first[fuel]
+			m, ok := MinMass(f, fuel)
+			if ok != exists || (ok && m != want) {
+				t.Fatalf("%s: MinMass(%d) = %d, %v, want %d, %v", name, fuel, m, ok, want, exists)
+			}
+		}
+	}
+}
+
+func TestMaxMasses(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for name, f := range fuelFuncs {
+		for i := 0; i < 200; i++ {
+			budget, n := rng.Intn(1<<30), 1+rng.Intn(50)
+			masses, err := MaxMasses(f, budget, n)
+			if err != nil {
+				t.Fatalf("%s: MaxMasses(%d, %d): %v", name, budget, n, err)
+			}
+			used := 0
+			for _, mass := range masses {
+				used += fuelNeeded(f, mass)
+			}
+			if used > budget {
+				t.Fatalf("%s: MaxMasses(%d, %d) uses %d fuel", name, budget, n, used)
+			}
+		}
+	}
+}
+
+func TestNegativeBudget(t *testing.T) {
+	if _, err := MaxMass(Required, -1); err == nil {
+		t.Error("MaxMass accepted a budget of -1")
+	}
+	if _, err := MaxMasses(Required, -1, 3); err == nil {
+		t.Error("MaxMasses accepted a budget of -1")
+	}
+}
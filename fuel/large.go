@@ -0,0 +1,38 @@
+package fuel
+
+import (
+	"math/big"
+)
+
+// RequiredRecursive64 is RequiredRecursive in 64 bits whatever the size of int.
+func RequiredRecursive64(mass int64) int64 {
+	var total int64
+	for fuel := mass/defaultDivisor - defaultSubtrahend; fuel > 0; fuel = fuel/defaultDivisor - defaultSubtrahend {
+		total += fuel
+	}
+	return total
+}
+
+var (
+	bigDivisor    = big.NewInt(defaultDivisor)
+	bigSubtrahend = big.NewInt(defaultSubtrahend)
+)
+
+// RequiredBig is Required for arbitrarily large masses.
+func RequiredBig(mass *big.Int) *big.Int {
+	fuel := new(big.Int).Quo(mass, bigDivisor)
+	return fuel.Sub(fuel, bigSubtrahend)
+}
+
+// RequiredRecursiveBig is RequiredRecursive for arbitrarily large masses. It
+// takes one division per level, so about log3(mass) of them.
+func RequiredRecursiveBig(mass *big.Int) *big.Int {
+	total := new(big.Int)
+	fuel := RequiredBig(mass)
+	for fuel.Sign() > 0 {
+		total.Add(total, fuel)
+		fuel.Quo(fuel, bigDivisor)
+		fuel.Sub(fuel, bigSubtrahend)
+	}
+	return total
+}
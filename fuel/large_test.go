@@ -0,0 +1,188 @@
+package fuel
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recursiveOriginal is the day 1 solution's fuelRequiredRecursive as first
+// written, kept to benchmark against.
+func recursiveOriginal(moduleMass int) int {
+	if moduleMass/3-2 <= 0 {
+		return 0
+	}
+	return moduleMass/3 - 2 + recursiveOriginal(moduleMass/3-2)
+}
+
+// randomMasses are n module masses like the puzzle's, from a fixed seed.
+func randomMasses(n int) []int {
+	rng := rand.New(rand.NewSource(1))
+	masses := make([]int, n)
+	for i := range masses {
+		masses[i] = 50000 + rng.Intn(100000)
+	}
+	return masses
+}
+
+// millionMasses are a million random masses, only generated for the benchmarks that use them.
+var millionMasses = sync.OnceValue(func() []int { return randomMasses(1000000) })
+
+func TestRecursiveVersionsAgree(t *testing.T) {
+	for _, mass := range append([]int{-5, 0, 8, 9, 14, 1969, 100756}, randomMasses(10000)...) {
+		want := recursiveOriginal(mass)
+		if got := RequiredRecursive(mass); got != want {
+			t.Fatalf("RequiredRecursive(%d) = %d, want %d", mass, got, want)
+		}
+		if got := RequiredRecursive64(int64(mass)); got != int64(want) {
+			t.Fatalf("RequiredRecursive64(%d) = %d, want %d", mass, got, want)
+		}
+		if got := RequiredRecursiveBig(big.NewInt(int64(mass))); got.Int64() != int64(want) {
+			t.Fatalf("RequiredRecursiveBig(%d) = %v, want %d", mass, got, want)
+		}
+	}
+}
+
+func TestRequiredRecursiveBig(t *testing.T) {
+	// 3^100 needs far more than 64 bits; check it against the same sum worked out term by term.
+	mass := new(big.Int).Exp(big.NewInt(3), big.NewInt(100), nil)
+	want := new(big.Int)
+	for fuel := RequiredBig(mass); fuel.Sign() > 0; fuel = RequiredBig(fuel) {
+		want.Add(want, fuel)
+	}
+	if got := RequiredRecursiveBig(mass); got.Cmp(want) != 0 {
+		t.Fatalf("RequiredRecursiveBig(3^100) = %v, want %v", got, want)
+	}
+	if got := RequiredRecursive64(1 << 62); got != RequiredRecursiveBig(big.NewInt(1<<62)).Int64() {
+		t.Fatalf("RequiredRecursive64(2^62) = %d disagrees with the big.Int version", got)
+	}
+}
+
+func TestSumBigMass(t *testing.T) {
+	huge := "100000000000000000000000000000"
+	totals, err := Sum(NewReader(strings.NewReader("12\n"+huge+"\n1969\n"), Strict), Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mass, _ := new(big.Int).SetString(huge, 10)
+	wantTotal := RequiredRecursiveBig(mass)
+	wantTotal.Add(wantTotal, big.NewInt(2+966))
+	if totals.Modules != 3 || totals.Total.Cmp(wantTotal) != 0 {
+		t.Fatalf("Sum = %d modules, total %v, want 3 and %v", totals.Modules, totals.Total, wantTotal)
+	}
+
+	if _, err := Sum(NewReader(strings.NewReader(huge+"\n"), Strict), Linear{Divisor: 4, Subtrahend: 1}); err == nil {
+		t.Fatal("want an error for a huge mass under a model other than Default")
+	}
+	if _, err := ReadMasses(strings.NewReader(huge + "\n")); !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("ReadMasses error = %v, want ErrRange", err)
+	}
+}
+
+func BenchmarkRecursiveOriginal(b *testing.B) {
+	masses := millionMasses()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		total := 0
+		for _, mass := range masses {
+			total += recursiveOriginal(mass)
+		}
+	}
+}
+
+func BenchmarkRequiredRecursive(b *testing.B) {
+	masses := millionMasses()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		total := 0
+		for _, mass := range masses {
+			total += RequiredRecursive(mass)
+		}
+	}
+}
+
+func BenchmarkRequiredRecursive64(b *testing.B) {
+	masses := millionMasses()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var total int64
+		for _, mass := range masses {
+			total += RequiredRecursive64(int64(mass))
+		}
+	}
+}
+
+func BenchmarkRequiredRecursiveBig(b *testing.B) {
+	masses := make([]*big.Int, len(millionMasses()))
+	for i, mass := range millionMasses() {
+		masses[i] = big.NewInt(int64(mass))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		total := new(big.Int)
+		for _, mass := range masses {
+			total.Add(total, RequiredRecursiveBig(mass))
+		}
+	}
+}
+
+func TestMemo(t *testing.T) {
+	rocket, _ := NewTsiolkovsky(300, 1000, Ceil)
+	for _, model := range []Model{Default, Linear{4, 1, Ceil}, rocket} {
+		memo := NewMemo(model, 100000)
+		for _, mass := range append(randomMasses(2000), randomMasses(2000)...) {
+			want, err := BreakdownWith(model, 0, mass)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fuel, total, err := memo.Fuel(mass)
+			if err != nil || fuel != want.Fuel || total != want.Total {
+				t.Fatalf("%+v: Fuel(%d) = %d, %d, %v, want %d, %d", model, mass, fuel, total, err, want.Fuel, want.Total)
+			}
+		}
+		// Masses at or above the bound aren't remembered, so only the first half are.
+		if n := memo.Len(); n == 0 || n >= 2000 {
+			t.Errorf("%+v: remembered %d masses", model, n)
+		}
+	}
+
+	memo := NewMemo(Linear{1, 0, Floor}, 100)
+	if _, _, err := memo.Fuel(50); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Fuel under a model that doesn't converge = %v", err)
+	}
+	if memo.Len() != 0 {
+		t.Error("remembered a mass that failed")
+	}
+	if fuel, total, err := NewMemo(Default, 0).Fuel(-5); err != nil || fuel != -3 || total != 0 {
+		t.Errorf("Fuel(-5) = %d, %d, %v, want -3, 0", fuel, total, err)
+	}
+}
+
+func BenchmarkBreakdownWith(b *testing.B) {
+	masses := millionMasses()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, mass := range masses {
+			if _, err := BreakdownWith(Default, 0, mass); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkMemo(b *testing.B) {
+	masses := millionMasses()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		memo := NewMemo(Default, memoBound)
+		for _, mass := range masses {
+			if _, _, err := memo.Fuel(mass); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
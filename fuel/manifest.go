@@ -0,0 +1,297 @@
+package fuel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Subsystem is a named part of a spacecraft made of modules and further subsystems.
+// A whole spacecraft is described by its top level subsystem, for example
+//
+//	{
+//	  "name": "spacecraft",
+//	  "subsystems": [
+//	    {"name": "propulsion", "tags": ["engine"], "modules": [
+//	      {"name": "main engine", "mass": 12000, "quantity": 2}
+//	    ]}
+//	  ],
+//	  "modules": [{"name": "cabin", "mass": 1969}]
+//	}
+type Subsystem struct {
+	Name       string       `json:"name"`
+	Tags       []string     `json:"tags,omitempty"`
+	Modules    []*Part      `json:"modules,omitempty"`
+	Subsystems []*Subsystem `json:"subsystems,omitempty"`
+}
+
+// MaxQuantity is the most copies of a part a manifest may ask for.
+const MaxQuantity = 1 << 30
+
+// Part is a kind of module, of which a subsystem has Quantity identical copies.
+type Part struct {
+	Name     string   `json:"name"`
+	Mass     int      `json:"mass"`
+	Quantity int      `json:"quantity,omitempty"` // 1 when not given
+	Tags     []string `json:"tags,omitempty"`
+	Line     int      `json:"-"` // line of a flat mass list the part was read from
+}
+
+// Key identifies the part within its manifest: its name, or "#<line>" for an
+// unnamed part from a flat mass list.
+func (p *Part) Key() string {
+	if p.Name == "" && p.Line > 0 {
+		return "#" + strconv.Itoa(p.Line)
+	}
+	return p.Name
+}
+
+// ReadManifest reads a JSON manifest or, for old inputs, a flat list of
+// masses, one per line, which becomes a single subsystem of unnamed modules.
+func ReadManifest(r io.Reader) (*Subsystem, error) {
+	// Peek rather than read past leading space so flat lists keep their line numbers.
+	buffered := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		peeked, _ := buffered.Peek(n)
+		if len(peeked) < n {
+			break
+		}
+		if b := peeked[n-1]; !strings.ContainsRune(" \t\r\n", rune(b)) {
+			if b == '{' {
+				return readJSONManifest(buffered)
+			}
+			break
+		}
+	}
+
+	root := &Subsystem{Name: "spacecraft"}
+	reader := NewReader(buffered, Strict)
+	for reader.Scan() {
+		if err := reader.tooLarge(); err != nil {
+			return nil, err
+		}
+		root.Modules = append(root.Modules, &Part{Mass: reader.Mass(), Quantity: 1, Line: reader.Line()})
+	}
+	return root, reader.Err()
+}
+
+func readJSONManifest(r io.Reader) (*Subsystem, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var root Subsystem
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("manifest: %v", err)
+	}
+	if err := root.validate(root.Name); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// validate checks names are present and unique among siblings, and fills in default quantities.
+func (s *Subsystem) validate(path string) error {
+	if s.Name == "" {
+		return fmt.Errorf("manifest: %s: subsystem without a name", path)
+	}
+	seen := make(map[string]bool)
+	for _, p := range s.Modules {
+		if p == nil {
+			return fmt.Errorf("manifest: %s: null module", path)
+		}
+		if p.Name == "" {
+			return fmt.Errorf("manifest: %s: module without a name", path)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("manifest: %s: more than one module or subsystem named %q", path, p.Name)
+		}
+		seen[p.Name] = true
+		if p.Quantity == 0 {
+			p.Quantity = 1
+		}
+		if p.Quantity < 0 || p.Mass < 0 {
+			return fmt.Errorf("manifest: %s/%s: mass and quantity must not be negative", path, p.Name)
+		}
+		if p.Quantity > MaxQuantity {
+			return fmt.Errorf("manifest: %s/%s: quantity %d is over the limit of %d", path, p.Name, p.Quantity, MaxQuantity)
+		}
+	}
+	for _, child := range s.Subsystems {
+		if child == nil {
+			return fmt.Errorf("manifest: %s: null subsystem", path)
+		}
+		if seen[child.Name] {
+			return fmt.Errorf("manifest: %s: more than one module or subsystem named %q", path, child.Name)
+		}
+		seen[child.Name] = true
+		if err := child.validate(path + "/" + child.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Walk calls visit with every part in the manifest, the path of the part
+// ("subsystem/.../key") and the tags it has including its subsystems' tags.
+func (s *Subsystem) Walk(visit func(path string, p *Part, tags []string)) {
+	s.walk("", nil, visit)
+}
+
+func (s *Subsystem) walk(prefix string, tags []string, visit func(string, *Part, []string)) {
+	tags = append(tags[:len(tags):len(tags)], s.Tags...)
+	for _, p := range s.Modules {
+		visit(prefix+p.Key(), p, append(tags[:len(tags):len(tags)], p.Tags...))
+	}
+	for _, child := range s.Subsystems {
+		child.walk(prefix+child.Name+"/", tags, visit)
+	}
+}
+
+// Rollup is the fuel of a subsystem and everything in it.
+type Rollup struct {
+	Name     string    `json:"name"`
+	Modules  int       `json:"modules"` // counting every copy
+	Mass     int       `json:"mass"`
+	Fuel     int       `json:"fuel"`
+	Total    int       `json:"total"`
+	Children []*Rollup `json:"children,omitempty"` // subsystems first, then modules
+}
+
+func (r *Rollup) add(child *Rollup) error {
+	if err := r.sum(child.Modules, child.Mass, child.Fuel, child.Total); err != nil {
+		return fmt.Errorf("%s/%s: totals overflow an int", r.Name, child.Name)
+	}
+	r.Children = append(r.Children, child)
+	return nil
+}
+
+// sum adds to the totals, failing rather than wrapping if any of them overflows.
+func (r *Rollup) sum(modules, mass, fuel, total int) error {
+	if !fits(r.Modules, modules) || !fits(r.Mass, mass) || !fits(r.Fuel, fuel) || !fits(r.Total, total) {
+		return fmt.Errorf("%s: totals overflow an int", r.Name)
+	}
+	r.Modules += modules
+	r.Mass += mass
+	r.Fuel += fuel
+	r.Total += total
+	return nil
+}
+
+// fits reports whether a+b is an int.
+func fits(a, b int) bool {
+	return (b <= 0 || a <= math.MaxInt-b) && (b >= 0 || a >= math.MinInt-b)
+}
+
+// copies returns the mass, fuel and total fuel of quantity copies of m, failing
+// rather than wrapping if any of them overflows.
+func copies(quantity int, m Module) (mass, fuel, total int, err error) {
+	values := []int{m.Mass, m.Fuel, m.Total}
+	for i, n := range values {
+		product := n * quantity
+		if n != 0 && (product/n != quantity || (n == -1 && quantity == math.MinInt)) {
+			return 0, 0, 0, fmt.Errorf("%d copies of mass %d overflow an int", quantity, m.Mass)
+		}
+		values[i] = product
+	}
+	return values[0], values[1], values[2], nil
+}
+
+// RollUp works out the fuel of every module under model, one copy at a time as
+// the puzzle does, and totals it for every subsystem.
+func RollUp(s *Subsystem, model Model) (*Rollup, error) {
+	r := &Rollup{Name: s.Name}
+	for _, child := range s.Subsystems {
+		childRollup, err := RollUp(child, model)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.add(childRollup); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range s.Modules {
+		m, err := BreakdownWith(model, p.Line, p.Mass)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %v", s.Name, p.Key(), err)
+		}
+		mass, fuel, total, err := copies(p.Quantity, m)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %v", s.Name, p.Key(), err)
+		}
+		err = r.add(&Rollup{Name: p.Key(), Modules: p.Quantity, Mass: mass, Fuel: fuel, Total: total})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// TagTotals rolls up the fuel of the modules carrying each tag, directly or through a subsystem.
+func TagTotals(s *Subsystem, model Model) ([]*Rollup, error) {
+	byTag := make(map[string]*Rollup)
+	var err error
+	s.Walk(func(path string, p *Part, tags []string) {
+		if err != nil {
+			return
+		}
+		m, breakdownErr := BreakdownWith(model, p.Line, p.Mass)
+		if breakdownErr != nil {
+			err = fmt.Errorf("%s: %v", path, breakdownErr)
+			return
+		}
+		mass, fuel, total, copiesErr := copies(p.Quantity, m)
+		if copiesErr != nil {
+			err = fmt.Errorf("%s: %v", path, copiesErr)
+			return
+		}
+		seen := make(map[string]bool)
+		for _, tag := range tags {
+			if seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			if byTag[tag] == nil {
+				byTag[tag] = &Rollup{Name: tag}
+			}
+			if err = byTag[tag].sum(p.Quantity, mass, fuel, total); err != nil {
+				return
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rollups []*Rollup
+	for _, r := range byTag {
+		rollups = append(rollups, r)
+	}
+	sort.Slice(rollups, func(i, j int) bool { return rollups[i].Name < rollups[j].Name })
+	return rollups, nil
+}
+
+// WriteTree writes the rollup as an indented tree, one subsystem or module per line.
+func (r *Rollup) WriteTree(w io.Writer) error {
+	return r.writeTree(w, 0)
+}
+
+func (r *Rollup) writeTree(w io.Writer, depth int) error {
+	_, err := fmt.Fprintf(w, "%s%s: %d modules, mass %d, fuel %d, total fuel %d\n", strings.Repeat("  ", depth), r.Name, r.Modules, r.Mass, r.Fuel, r.Total)
+	if err != nil {
+		return err
+	}
+	for _, child := range r.Children {
+		if err := child.writeTree(w, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package fuel
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const exampleManifest = `{
+  "name": "spacecraft",
+  "tags": ["crewed"],
+  "subsystems": [
+    {"name": "propulsion", "tags": ["engine"], "modules": [
+      {"name": "main engine", "mass": 12000, "quantity": 2},
+      {"name": "thruster", "mass": 14, "tags": ["rcs", "engine"]}
+    ]}
+  ],
+  "modules": [{"name": "cabin", "mass": 1969}]
+}`
+
+func TestReadManifest(t *testing.T) {
+	root, err := ReadManifest(strings.NewReader("\n  " + exampleManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type visit struct {
+		path     string
+		quantity int
+		tags     []string
+	}
+	var visits []visit
+	root.Walk(func(path string, p *Part, tags []string) {
+		visits = append(visits, visit{path, p.Quantity, tags})
+	})
+	want := []visit{
+		{"cabin", 1, []string{"crewed"}},
+		{"propulsion/main engine", 2, []string{"crewed", "engine"}},
+		{"propulsion/thruster", 1, []string{"crewed", "engine", "rcs", "engine"}},
+	}
+	if !reflect.DeepEqual(visits, want) {
+		t.Errorf("Walk visited %v, want %v", visits, want)
+	}
+}
+
+func TestReadFlatManifest(t *testing.T) {
+	root, err := ReadManifest(strings.NewReader("\n  12\n1969\n\n14\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	root.Walk(func(path string, p *Part, _ []string) {
+		keys = append(keys, path)
+	})
+	if want := []string{"#2", "#3", "#5"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("flat manifest parts %v, want %v", keys, want)
+	}
+	if _, err := ReadManifest(strings.NewReader("12\nabc\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error for a bad flat line = %v", err)
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	for _, test := range []struct{ manifest, err string }{
+		{`{"modules": []}`, "subsystem without a name"},
+		{`{"name": "s", "modules": [{"mass": 1}]}`, "module without a name"},
+		{`{"name": "s", "modules": [{"name": "a"}], "subsystems": [{"name": "a"}]}`, `more than one module or subsystem named "a"`},
+		{`{"name": "s", "modules": [{"name": "a", "mass": -1}]}`, "must not be negative"},
+		{`{"name": "s", "subsystems": [{"name": "t", "modules": [{"name": "a", "quantity": -2}]}]}`, "s/t/a: mass and quantity must not be negative"},
+		{`{"name": "s", "mass": 12}`, "unknown field"},
+		{`{"name": "s", "modules": [null]}`, "null module"},
+	} {
+		if _, err := ReadManifest(strings.NewReader(test.manifest)); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ReadManifest(%s) = %v, want an error containing %q", test.manifest, err, test.err)
+		}
+	}
+}
+
+func TestRollUp(t *testing.T) {
+	root, err := ReadManifest(strings.NewReader(exampleManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rollup, err := RollUp(root, Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := rollup.WriteTree(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `spacecraft: 4 modules, mass 25983, fuel 8652, total fuel 12918
+  propulsion: 3 modules, mass 24014, fuel 7998, total fuel 11952
+    main engine: 2 modules, mass 24000, fuel 7996, total fuel 11950
+    thruster: 1 modules, mass 14, fuel 2, total fuel 2
+  cabin: 1 modules, mass 1969, fuel 654, total fuel 966
+`
+	if b.String() != want {
+		t.Errorf("rollup:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	tags, err := TagTotals(root, Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Rollup
+	for _, r := range tags {
+		got = append(got, *r)
+	}
+	wantTags := []Rollup{
+		{Name: "crewed", Modules: 4, Mass: 25983, Fuel: 8652, Total: 12918},
+		{Name: "engine", Modules: 3, Mass: 24014, Fuel: 7998, Total: 11952},
+		{Name: "rcs", Modules: 1, Mass: 14, Fuel: 2, Total: 2},
+	}
+	if !reflect.DeepEqual(got, wantTags) {
+		t.Errorf("TagTotals = %+v, want %+v", got, wantTags)
+	}
+
+	if _, err := RollUp(root, Linear{1, 0, Floor}); err == nil || !strings.Contains(err.Error(), "does not converge") {
+		t.Errorf("RollUp under a model that doesn't converge = %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package fuel
+
+// Memo remembers the base and total fuel under a model of every mass below a
+// bound, so an input that repeats masses, as large inputs of similar modules
+// do, works each one out once. Masses at or above the bound, and the fuel
+// for fuel of any mass, are worked out every time. A Memo is not safe for
+// concurrent use.
+type Memo struct {
+	model Model
+	bound int
+	fuel  []int
+	total []int
+	known []bool
+}
+
+// NewMemo returns a Memo for model that remembers masses from 0 up to bound.
+func NewMemo(model Model, bound int) *Memo {
+	return &Memo{model: model, bound: max(bound, 0)}
+}
+
+// Fuel returns the base fuel for mass and its total including fuel for fuel,
+// the Fuel and Total of BreakdownWith.
+func (m *Memo) Fuel(mass int) (fuel, total int, err error) {
+	if mass < 0 || mass >= m.bound {
+		return m.work(mass)
+	}
+	if mass >= len(m.known) {
+		// Grow to twice the mass seen so the table tracks the input, not the bound.
+		size := min(max(2*mass, 1024), m.bound)
+		m.fuel = append(m.fuel, make([]int, size-len(m.fuel))...)
+		m.total = append(m.total, make([]int, size-len(m.total))...)
+		m.known = append(m.known, make([]bool, size-len(m.known))...)
+	}
+	if m.known[mass] {
+		return m.fuel[mass], m.total[mass], nil
+	}
+	fuel, total, err = m.work(mass)
+	if err != nil {
+		return 0, 0, err
+	}
+	m.fuel[mass], m.total[mass], m.known[mass] = fuel, total, true
+	return fuel, total, nil
+}
+
+// work is Fuel without the table.
+func (m *Memo) work(mass int) (int, int, error) {
+	breakdown, err := BreakdownWith(m.model, 0, mass)
+	return breakdown.Fuel, breakdown.Total, err
+}
+
+// Len is the number of masses remembered.
+func (m *Memo) Len() int {
+	n := 0
+	for _, known := range m.known {
+		if known {
+			n++
+		}
+	}
+	return n
+}
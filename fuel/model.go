@@ -0,0 +1,155 @@
+package fuel
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Model turns a mass into the fuel needed to launch it. Results of zero or
+// less mean no fuel is needed.
+type Model interface {
+	Fuel(mass int) int
+}
+
+// Rounding is how a model turns a fractional amount of fuel into a whole one.
+type Rounding int
+
+const (
+	Floor Rounding = iota
+	Ceil
+)
+
+// ParseRounding parses "floor" or "ceil".
+func ParseRounding(name string) (Rounding, error) {
+	switch name {
+	case "floor":
+		return Floor, nil
+	case "ceil":
+		return Ceil, nil
+	}
+	return Floor, fmt.Errorf("unknown rounding %q, want floor or ceil", name)
+}
+
+func (r Rounding) round(val float64) int {
+	if r == Ceil {
+		return int(math.Ceil(val))
+	}
+	return int(math.Floor(val))
+}
+
+// Linear is the day 1 formula generalised: divide the mass by Divisor, round,
+// and subtract Subtrahend.
+type Linear struct {
+	Divisor    int
+	Subtrahend int
+	Rounding   Rounding
+}
+
+// Default is the model from the puzzle: divide by three, round down and subtract 2.
+var Default = Linear{Divisor: defaultDivisor, Subtrahend: defaultSubtrahend, Rounding: Floor}
+
+// NewLinear returns a linear model, rejecting divisors that don't make sense.
+func NewLinear(divisor, subtrahend int, rounding Rounding) (Linear, error) {
+	if divisor < 1 {
+		return Linear{}, fmt.Errorf("divisor must be at least 1, got %d", divisor)
+	}
+	return Linear{Divisor: divisor, Subtrahend: subtrahend, Rounding: rounding}, nil
+}
+
+// exact is the fuel for mass before rounding.
+func (l Linear) exact(mass int) float64 {
+	return float64(mass)/float64(l.Divisor) - float64(l.Subtrahend)
+}
+
+func (l Linear) Fuel(mass int) int {
+	quotient := mass / l.Divisor
+	if l.Rounding == Ceil && mass%l.Divisor > 0 {
+		quotient++
+	}
+	return quotient - l.Subtrahend
+}
+
+// standardGravity converts specific impulse in seconds to exhaust velocity in m/s.
+const standardGravity = 9.80665
+
+// Tsiolkovsky uses the rocket equation: reaching DeltaV (m/s) with an engine of
+// specific impulse Isp (s) takes mass * (e^(DeltaV / (Isp * g0)) - 1) of propellant.
+type Tsiolkovsky struct {
+	Isp      float64
+	DeltaV   float64
+	Rounding Rounding
+}
+
+// NewTsiolkovsky returns a rocket equation model, rejecting impossible engines and manoeuvres.
+func NewTsiolkovsky(isp, deltaV float64, rounding Rounding) (Tsiolkovsky, error) {
+	if isp <= 0 {
+		return Tsiolkovsky{}, fmt.Errorf("specific impulse must be positive, got %g", isp)
+	}
+	if deltaV < 0 {
+		return Tsiolkovsky{}, fmt.Errorf("delta-v must not be negative, got %g", deltaV)
+	}
+	return Tsiolkovsky{Isp: isp, DeltaV: deltaV, Rounding: rounding}, nil
+}
+
+// massRatio is the propellant needed per unit of mass.
+func (t Tsiolkovsky) massRatio() float64 {
+	return math.Expm1(t.DeltaV / (t.Isp * standardGravity))
+}
+
+func (t Tsiolkovsky) exact(mass int) float64 {
+	return float64(mass) * t.massRatio()
+}
+
+func (t Tsiolkovsky) Fuel(mass int) int {
+	return t.Rounding.round(float64(mass) * t.massRatio())
+}
+
+// ErrNoConvergence is returned when the fuel for fuel never reaches zero
+// because a model needs at least as much fuel as the mass it launches.
+var ErrNoConvergence = errors.New("fuel for fuel does not converge")
+
+// exactModel is a Model that can also give its fuel before rounding.
+type exactModel interface {
+	exact(mass int) float64
+}
+
+// fuelForFuel returns the base fuel for mass under model and every further
+// amount of fuel needed for the previous one. Each amount must be smaller than
+// the one before, which guarantees the sequence ends. Rounding up can stop an
+// amount shrinking even though the exact fuel for it is smaller, typically one
+// unit needing a fraction of a unit more; the sequence ends there instead.
+func fuelForFuel(model Model, mass int) (int, []int, error) {
+	base := model.Fuel(mass)
+	if base <= 0 {
+		return base, nil, nil
+	}
+	var extras []int
+	for prev := base; ; {
+		extra := model.Fuel(prev)
+		if extra <= 0 {
+			return base, extras, nil
+		}
+		if extra >= prev {
+			if e, ok := model.(exactModel); ok && e.exact(prev) < float64(prev) {
+				return base, extras, nil
+			}
+			return base, nil, fmt.Errorf("mass %d: %d fuel needs %d more: %w", mass, prev, extra, ErrNoConvergence)
+		}
+		extras = append(extras, extra)
+		prev = extra
+	}
+}
+
+// Recursive is the fuel needed to launch mass and all the fuel added for it under model.
+func Recursive(model Model, mass int) (int, error) {
+	base, extras, err := fuelForFuel(model, mass)
+	if err != nil || base <= 0 {
+		return 0, err
+	}
+	total := base
+	for _, extra := range extras {
+		total += extra
+	}
+	return total, nil
+}
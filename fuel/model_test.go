@@ -0,0 +1,94 @@
+package fuel
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestModels(t *testing.T) {
+	rocket, err := NewTsiolkovsky(300, 1000, Floor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rocketCeil := rocket
+	rocketCeil.Rounding = Ceil
+	for _, test := range []struct {
+		name  string
+		model Model
+		mass  int
+		want  int
+	}{
+		{"default 12", Default, 12, 2},
+		{"default 14", Default, 14, 2},
+		{"default 1969", Default, 1969, 654},
+		{"default small", Default, 2, -2},
+		{"ceil 12", Linear{3, 2, Ceil}, 12, 2},
+		{"ceil 14", Linear{3, 2, Ceil}, 14, 3},
+		{"ceil 1", Linear{3, 0, Ceil}, 1, 1},
+		{"rocket", rocket, 1000, 404},
+		{"rocket ceil", rocketCeil, 1000, 405},
+		{"rocket zero", rocket, 0, 0},
+	} {
+		if got := test.model.Fuel(test.mass); got != test.want {
+			t.Errorf("%s: Fuel(%d) = %d, want %d", test.name, test.mass, got, test.want)
+		}
+	}
+}
+
+func TestModelConstructors(t *testing.T) {
+	if _, err := NewLinear(0, 2, Floor); err == nil {
+		t.Error("NewLinear accepted a divisor of 0")
+	}
+	if _, err := NewTsiolkovsky(0, 1000, Floor); err == nil {
+		t.Error("NewTsiolkovsky accepted a specific impulse of 0")
+	}
+	if _, err := NewTsiolkovsky(300, -1, Floor); err == nil {
+		t.Error("NewTsiolkovsky accepted a negative delta-v")
+	}
+	if r, err := ParseRounding("ceil"); err != nil || r != Ceil {
+		t.Errorf("ParseRounding(ceil) = %v, %v", r, err)
+	}
+	if _, err := ParseRounding("up"); err == nil {
+		t.Error("ParseRounding accepted \"up\"")
+	}
+}
+
+func TestFuelForFuel(t *testing.T) {
+	rocket, _ := NewTsiolkovsky(300, 1000, Floor)
+	rocketCeil, _ := NewTsiolkovsky(300, 1000, Ceil)
+	for _, test := range []struct {
+		name   string
+		model  Model
+		mass   int
+		base   int
+		extras []int
+	}{
+		{"default", Default, 1969, 654, []int{216, 70, 21, 5}},
+		{"default small", Default, 12, 2, nil},
+		{"none", Default, 5, -1, nil},
+		// Rounding up would need 1 unit for every 1 unit forever; stop there.
+		{"ceil", Linear{3, 0, Ceil}, 1969, 657, []int{219, 73, 25, 9, 3, 1}},
+		{"rocket", rocket, 1000, 404, []int{163, 65, 26, 10, 4, 1}},
+		{"rocket ceil", rocketCeil, 1000, 405, []int{164, 67, 28, 12, 5, 3, 2, 1}},
+		// Rounding up a ratio of 0.9 sticks at 5, which needs 4.5 more.
+		{"rocket ceil stuck above 1", Tsiolkovsky{Isp: 1 / standardGravity, DeltaV: 0.6418538861723947, Rounding: Ceil}, 5, 5, nil},
+	} {
+		base, extras, err := fuelForFuel(test.model, test.mass)
+		if err != nil || base != test.base || !reflect.DeepEqual(extras, test.extras) {
+			t.Errorf("%s: fuelForFuel(%d) = %d, %v, %v, want %d, %v", test.name, test.mass, base, extras, err, test.base, test.extras)
+		}
+	}
+}
+
+func TestNoConvergence(t *testing.T) {
+	expensive, _ := NewTsiolkovsky(300, 5000, Ceil) // needs more propellant than the mass
+	for _, model := range []Model{Linear{1, 0, Floor}, Linear{1, 0, Ceil}, Linear{3, -5, Floor}, expensive} {
+		if _, err := Recursive(model, 1969); !errors.Is(err, ErrNoConvergence) {
+			t.Errorf("Recursive(%+v) = %v, want ErrNoConvergence", model, err)
+		}
+	}
+	if total, err := Recursive(Default, 100756); err != nil || total != 50346 {
+		t.Errorf("Recursive(Default, 100756) = %d, %v, want 50346", total, err)
+	}
+}
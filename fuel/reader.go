@@ -0,0 +1,212 @@
+package fuel
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Policy is what a Reader does with a line that isn't a mass.
+type Policy int
+
+const (
+	// Strict stops reading at the first bad line.
+	Strict Policy = iota
+	// Skip records the bad line and carries on.
+	Skip
+)
+
+// maxLineErrors bounds how many skipped line errors a Reader keeps; further ones are only counted.
+const maxLineErrors = 100
+
+// LineError is a line of input that isn't a module mass.
+type LineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: module mass %q: %v", e.Line, e.Text, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Reader streams module masses, one per line, ignoring blank lines. Use it like
+// a bufio.Scanner: call Scan until it returns false, then check Err.
+type Reader struct {
+	scanner *bufio.Scanner
+	policy  Policy
+	line    int
+	mass    int
+	bigMass *big.Int
+	err     error
+
+	// LineErrors are the first bad lines skipped under the Skip policy, and
+	// Skipped counts all of them.
+	LineErrors []*LineError
+	Skipped    int
+}
+
+// NewReader returns a Reader of r that handles bad lines according to policy.
+func NewReader(r io.Reader, policy Policy) *Reader {
+	return &Reader{scanner: bufio.NewScanner(r), policy: policy}
+}
+
+// Scan advances to the next mass, returning false at the end of input or on an error.
+func (r *Reader) Scan() bool {
+	if r.err != nil {
+		return false
+	}
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+		mass, err := strconv.Atoi(text)
+		if err == nil {
+			r.mass, r.bigMass = mass, nil
+			return true
+		}
+		if bigMass, ok := new(big.Int).SetString(text, 10); ok {
+			r.mass, r.bigMass = 0, bigMass
+			return true
+		}
+		lineErr := &LineError{Line: r.line, Text: text, Err: err.(*strconv.NumError).Err}
+		if r.policy == Strict {
+			r.err = lineErr
+			return false
+		}
+		r.Skipped++
+		if len(r.LineErrors) < maxLineErrors {
+			r.LineErrors = append(r.LineErrors, lineErr)
+		}
+	}
+	r.err = r.scanner.Err()
+	return false
+}
+
+// Mass is the mass read by the last successful Scan.
+func (r *Reader) Mass() int {
+	return r.mass
+}
+
+// BigMass is the mass read by the last successful Scan when it is too large
+// for an int, in which case Mass is 0. It is nil otherwise.
+func (r *Reader) BigMass() *big.Int {
+	return r.bigMass
+}
+
+// tooLarge is the error for a mass read by the last Scan that doesn't fit an int, if it doesn't.
+func (r *Reader) tooLarge() error {
+	if r.bigMass == nil {
+		return nil
+	}
+	return &LineError{Line: r.line, Text: r.bigMass.String(), Err: strconv.ErrRange}
+}
+
+// Line is the line number of the last line read, from 1.
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// Err is the error that stopped the Reader, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// ReadMasses reads every mass from r, stopping at the first bad line.
+func ReadMasses(r io.Reader) ([]int, error) {
+	var masses []int
+	reader := NewReader(r, Strict)
+	for reader.Scan() {
+		if err := reader.tooLarge(); err != nil {
+			return masses, err
+		}
+		masses = append(masses, reader.Mass())
+	}
+	return masses, reader.Err()
+}
+
+// Totals are the sums over any number of modules, kept in big integers so they can't overflow.
+type Totals struct {
+	Modules int64
+	Mass    *big.Int
+	Fuel    *big.Int // sum of the base fuel, the day 1 part 1 answer
+	Total   *big.Int // sum of all fuel including fuel for fuel, the day 1 part 2 answer
+}
+
+func newTotals() *Totals {
+	return &Totals{Mass: new(big.Int), Fuel: new(big.Int), Total: new(big.Int)}
+}
+
+// Add adds a module's breakdown to the totals.
+func (t *Totals) Add(m Module) {
+	t.Modules++
+	t.Mass.Add(t.Mass, big.NewInt(int64(m.Mass)))
+	t.Fuel.Add(t.Fuel, big.NewInt(int64(m.Fuel)))
+	t.Total.Add(t.Total, big.NewInt(int64(m.Total)))
+}
+
+// addBig adds a module too heavy for an int, with its base and total fuel.
+func (t *Totals) addBig(mass, fuel, total *big.Int) {
+	t.Modules++
+	t.Mass.Add(t.Mass, mass)
+	t.Fuel.Add(t.Fuel, fuel)
+	t.Total.Add(t.Total, total)
+}
+
+// Merge adds other's totals into t.
+func (t *Totals) Merge(other *Totals) {
+	t.Modules += other.Modules
+	t.Mass.Add(t.Mass, other.Mass)
+	t.Fuel.Add(t.Fuel, other.Fuel)
+	t.Total.Add(t.Total, other.Total)
+}
+
+// Sum streams every module from r and totals its fuel under model, holding
+// only one module in memory at a time. Masses too large for an int are
+// totalled in big integers under the Default model and rejected under others.
+func Sum(r *Reader, model Model) (*Totals, error) {
+	return SumContext(context.Background(), r, model)
+}
+
+// cancelCheckInterval is how many modules SumContext reads between checks for cancellation.
+const cancelCheckInterval = 4096
+
+// memoBound is the bound of the Memo SumContext works out fuel with, which
+// covers the masses of puzzle-like inputs.
+const memoBound = 1 << 18
+
+// SumContext is Sum, stopping early with the context's error if ctx is cancelled.
+func SumContext(ctx context.Context, r *Reader, model Model) (*Totals, error) {
+	totals := newTotals()
+	memo := NewMemo(model, memoBound)
+	for scanned := 0; r.Scan(); scanned++ {
+		if scanned%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return totals, err
+			}
+		}
+		if mass := r.BigMass(); mass != nil {
+			if model != Model(Default) {
+				return totals, fmt.Errorf("line %d: mass %v is too large for this fuel model", r.Line(), mass)
+			}
+			totals.addBig(mass, RequiredBig(mass), RequiredRecursiveBig(mass))
+			continue
+		}
+		fuel, total, err := memo.Fuel(r.Mass())
+		if err != nil {
+			return totals, fmt.Errorf("line %d: %v", r.Line(), err)
+		}
+		totals.Add(Module{Number: r.Line(), Mass: r.Mass(), Fuel: fuel, Total: total})
+	}
+	return totals, r.Err()
+}
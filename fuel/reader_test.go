@@ -0,0 +1,64 @@
+package fuel
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// spacedMasses is n masses with a blank line before each, so every mass is on an even line.
+func spacedMasses(n int) string {
+	return strings.Repeat("\n100756", n)
+}
+
+func TestSumContextCancelledWithBlankLines(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	totals, err := SumContext(ctx, NewReader(strings.NewReader(spacedMasses(3*cancelCheckInterval)), Strict), Default)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if totals.Modules != 0 {
+		t.Errorf("summed %d modules after cancellation", totals.Modules)
+	}
+}
+
+func TestSumContext(t *testing.T) {
+	totals, err := SumContext(context.Background(), NewReader(strings.NewReader(spacedMasses(10)), Strict), Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals.Modules != 10 || totals.Fuel.Int64() != 10*33583 || totals.Total.Int64() != 10*50346 {
+		t.Errorf("totals = %d modules, fuel %v, total %v", totals.Modules, totals.Fuel, totals.Total)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	input := "12\nabc\n\n1969\n12.5\n-14\n" + strings.Repeat("x\n", maxLineErrors) + "100756\n"
+	r := NewReader(strings.NewReader(input), Skip)
+	totals, err := Sum(r, Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals.Modules != 4 || totals.Fuel.Int64() != 2+654-6+33583 || totals.Total.Int64() != 2+966+50346 {
+		t.Errorf("totals = %d modules, fuel %v, total %v", totals.Modules, totals.Fuel, totals.Total)
+	}
+	if r.Skipped != 2+maxLineErrors {
+		t.Errorf("skipped %d lines, want %d", r.Skipped, 2+maxLineErrors)
+	}
+	if len(r.LineErrors) != maxLineErrors {
+		t.Fatalf("kept %d line errors, want the first %d", len(r.LineErrors), maxLineErrors)
+	}
+	for i, want := range []LineError{{Line: 2, Text: "abc", Err: strconv.ErrSyntax}, {Line: 5, Text: "12.5", Err: strconv.ErrSyntax}, {Line: 7, Text: "x", Err: strconv.ErrSyntax}} {
+		if got := r.LineErrors[i]; *got != want {
+			t.Errorf("LineErrors[%d] = %+v, want %+v", i, *got, want)
+		}
+	}
+
+	r = NewReader(strings.NewReader(input), Strict)
+	if _, err := Sum(r, Default); err == nil || err.Error() != `line 2: module mass "abc": invalid syntax` {
+		t.Errorf("Strict error = %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package fuel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServiceOptions limits what a fuel service accepts.
+type ServiceOptions struct {
+	MaxBytes   int64 // largest request body
+	MaxModules int   // most modules in one request, counting every copy in a manifest
+}
+
+// DefaultServiceOptions are the limits used for zero fields of ServiceOptions.
+var DefaultServiceOptions = ServiceOptions{MaxBytes: 1 << 20, MaxModules: 100000}
+
+// FuelRequest is the body of a request to the fuel service: either a list of
+// masses or a manifest.
+type FuelRequest struct {
+	Masses   []int      `json:"masses,omitempty"`
+	Manifest *Subsystem `json:"manifest,omitempty"`
+}
+
+// Service is an HTTP JSON API for the fuel calculations:
+//
+//	POST /fuel     {"masses": [12, 1969]} gives a Report,
+//	               {"manifest": {...}} gives a Rollup
+//	GET  /metrics  request counts and timings in the Prometheus text format
+//	GET  /healthz  "ok"
+type Service struct {
+	opts ServiceOptions
+	mux  *http.ServeMux
+
+	mu       sync.Mutex
+	requests map[int]int64 // by status code
+	modules  int64
+	seconds  float64
+}
+
+func NewService(opts ServiceOptions) *Service {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultServiceOptions.MaxBytes
+	}
+	if opts.MaxModules <= 0 {
+		opts.MaxModules = DefaultServiceOptions.MaxModules
+	}
+	s := &Service{opts: opts, mux: http.NewServeMux(), requests: make(map[int]int64)}
+	s.mux.HandleFunc("/fuel", s.handleFuel)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+	s.mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	return s
+}
+
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// requestError is a problem with the request, reported to the client with its status code.
+type requestError struct {
+	status int
+	msg    string
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+func (s *Service) handleFuel(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	modules, result, err := s.fuel(w, r)
+	status := http.StatusOK
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		status = http.StatusInternalServerError
+		var reqErr *requestError
+		if errors.As(err, &reqErr) {
+			status = reqErr.status
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	} else {
+		json.NewEncoder(w).Encode(result)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[status]++
+	s.modules += int64(modules)
+	s.seconds += time.Since(start).Seconds()
+}
+
+// fuel validates a request and works out its fuel, returning how many modules it had.
+func (s *Service) fuel(w http.ResponseWriter, r *http.Request) (int, interface{}, error) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		return 0, nil, &requestError{http.StatusMethodNotAllowed, "use POST"}
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.opts.MaxBytes))
+	decoder.DisallowUnknownFields()
+	var req FuelRequest
+	if err := decoder.Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return 0, nil, &requestError{http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is over %d bytes", s.opts.MaxBytes)}
+		}
+		return 0, nil, badRequest("invalid request: %v", err)
+	}
+
+	switch {
+	case req.Manifest != nil && req.Masses != nil:
+		return 0, nil, badRequest("give masses or a manifest, not both")
+	case req.Manifest != nil:
+		if err := req.Manifest.validate(req.Manifest.Name); err != nil {
+			return 0, nil, badRequest("%v", err)
+		}
+		modules, over := 0, false
+		req.Manifest.Walk(func(_ string, p *Part, _ []string) {
+			// Check before adding so that huge quantities can't wrap the count.
+			if over || p.Quantity > s.opts.MaxModules-modules {
+				over = true
+				return
+			}
+			modules += p.Quantity
+		})
+		if over {
+			return modules, nil, badRequest("manifest has more than the limit of %d modules", s.opts.MaxModules)
+		}
+		rollup, err := RollUp(req.Manifest, Default)
+		if err != nil {
+			return modules, nil, badRequest("%v", err)
+		}
+		return modules, rollup, nil
+	case req.Masses != nil:
+		if len(req.Masses) > s.opts.MaxModules {
+			return len(req.Masses), nil, badRequest("%d modules is more than the limit of %d", len(req.Masses), s.opts.MaxModules)
+		}
+		for i, mass := range req.Masses {
+			if mass < 0 {
+				return 0, nil, badRequest("masses[%d]: mass %d is negative", i, mass)
+			}
+		}
+		report, err := NewReportWith(Default, req.Masses)
+		if err != nil {
+			return len(req.Masses), nil, badRequest("masses: %v", err)
+		}
+		return len(req.Masses), report, nil
+	}
+	return 0, nil, badRequest("give masses or a manifest")
+}
+
+func (s *Service) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP fuel_requests_total Fuel requests handled, by HTTP status code.\n")
+	b.WriteString("# TYPE fuel_requests_total counter\n")
+	var statuses []int
+	var count int64
+	for status, n := range s.requests {
+		statuses = append(statuses, status)
+		count += n
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "fuel_requests_total{code=\"%d\"} %d\n", status, s.requests[status])
+	}
+	b.WriteString("# HELP fuel_modules_total Modules in fuel requests.\n")
+	b.WriteString("# TYPE fuel_modules_total counter\n")
+	fmt.Fprintf(&b, "fuel_modules_total %d\n", s.modules)
+	b.WriteString("# HELP fuel_request_duration_seconds Time spent handling fuel requests.\n")
+	b.WriteString("# TYPE fuel_request_duration_seconds summary\n")
+	fmt.Fprintf(&b, "fuel_request_duration_seconds_sum %g\n", s.seconds)
+	fmt.Fprintf(&b, "fuel_request_duration_seconds_count %d\n", count)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprint(w, b.String())
+}
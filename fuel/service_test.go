@@ -0,0 +1,149 @@
+package fuel
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func post(t *testing.T, server *httptest.Server, body string) (int, map[string]interface{}) {
+	t.Helper()
+	resp, err := server.Client().Post(server.URL+"/fuel", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decoding response to %s: %v", body, err)
+	}
+	return resp.StatusCode, result
+}
+
+func TestServiceMasses(t *testing.T) {
+	server := httptest.NewServer(NewService(ServiceOptions{}))
+	defer server.Close()
+
+	status, result := post(t, server, `{"masses": [12, 1969, 100756]}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, %v", status, result)
+	}
+	if result["fuel"] != float64(2+654+33583) || result["total"] != float64(2+966+50346) {
+		t.Errorf("fuel %v, total %v", result["fuel"], result["total"])
+	}
+	if modules, _ := result["modules"].([]interface{}); len(modules) != 3 {
+		t.Errorf("report has %d modules, want 3", len(modules))
+	}
+}
+
+func TestServiceManifest(t *testing.T) {
+	server := httptest.NewServer(NewService(ServiceOptions{}))
+	defer server.Close()
+
+	status, result := post(t, server, `{"manifest": {
+		"name": "spacecraft",
+		"subsystems": [{"name": "propulsion", "modules": [{"name": "engine", "mass": 1969, "quantity": 2}]}],
+		"modules": [{"name": "cabin", "mass": 12}]
+	}}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, %v", status, result)
+	}
+	if result["name"] != "spacecraft" || result["modules"] != float64(3) || result["total"] != float64(2*966+2) {
+		t.Errorf("rollup = %v", result)
+	}
+	children, _ := result["children"].([]interface{})
+	if len(children) != 2 || children[0].(map[string]interface{})["name"] != "propulsion" {
+		t.Errorf("children = %v, want propulsion then cabin", children)
+	}
+}
+
+func TestServiceErrors(t *testing.T) {
+	server := httptest.NewServer(NewService(ServiceOptions{MaxBytes: 200, MaxModules: 4}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		name, body string
+		status     int
+		err        string
+	}{
+		{"too large", `{"masses": [` + strings.Repeat("1,", 200) + `1]}`, http.StatusRequestEntityTooLarge, "over 200 bytes"},
+		{"too many masses", `{"masses": [1, 2, 3, 4, 5]}`, http.StatusBadRequest, "limit of 4"},
+		{"too many copies", `{"manifest": {"name": "s", "modules": [{"name": "a", "mass": 1, "quantity": 5}]}}`, http.StatusBadRequest, "limit of 4"},
+		{"quantity overflow", `{"manifest": {"name": "s", "modules": [
+			{"name": "a", "mass": 1, "quantity": 4611686018427387904},
+			{"name": "b", "mass": 1, "quantity": 4611686018427387904}]}}`, http.StatusBadRequest, "over the limit"},
+		{"unknown field", `{"masses": [12], "mas": 1}`, http.StatusBadRequest, "unknown field"},
+		{"null module", `{"manifest": {"name": "s", "modules": [null]}}`, http.StatusBadRequest, "null module"},
+		{"null subsystem", `{"manifest": {"name": "s", "subsystems": [null]}}`, http.StatusBadRequest, "null subsystem"},
+		{"both", `{"masses": [12], "manifest": {"name": "s"}}`, http.StatusBadRequest, "not both"},
+		{"negative mass", `{"masses": [-1]}`, http.StatusBadRequest, "negative"},
+		{"mass overflow", `{"masses": [9223372036854775807, 9223372036854775807]}`, http.StatusBadRequest, "module 2: totals overflow"},
+		{"rollup overflow", `{"manifest": {"name": "s", "modules": [
+			{"name": "a", "mass": 9223372036854775807},
+			{"name": "b", "mass": 9223372036854775807}]}}`, http.StatusBadRequest, "s/b: totals overflow"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			status, result := post(t, server, test.body)
+			if status != test.status {
+				t.Errorf("status = %d, want %d", status, test.status)
+			}
+			if msg, _ := result["error"].(string); !strings.Contains(msg, test.err) {
+				t.Errorf("error = %q, want it to mention %q", msg, test.err)
+			}
+		})
+	}
+
+	resp, err := server.Client().Get(server.URL + "/fuel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodPost {
+		t.Errorf("GET /fuel = %d, Allow %q, want 405 and POST", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+}
+
+func TestRollUpOverflow(t *testing.T) {
+	manifest := &Subsystem{Name: "s", Modules: []*Part{{Name: "a", Mass: 1 << 40, Quantity: MaxQuantity}}}
+	if _, err := RollUp(manifest, Default); err == nil || !strings.Contains(err.Error(), "overflow") {
+		t.Errorf("RollUp error = %v, want an overflow", err)
+	}
+	if _, err := TagTotals(&Subsystem{Name: "s", Tags: []string{"t"}, Modules: manifest.Modules}, Default); err == nil {
+		t.Error("TagTotals should fail on an overflow")
+	}
+	if _, err := ReadManifest(strings.NewReader(`{"name": "s", "subsystems": [{"name": "t", "modules": [null]}]}`)); err == nil {
+		t.Error("ReadManifest accepted a null module")
+	}
+}
+
+func TestServiceMetrics(t *testing.T) {
+	server := httptest.NewServer(NewService(ServiceOptions{MaxModules: 4}))
+	defer server.Close()
+
+	post(t, server, `{"masses": [12, 14]}`)
+	post(t, server, `{"manifest": {"name": "s", "modules": [{"name": "a", "mass": 1969, "quantity": 3}]}}`)
+	post(t, server, `{"masses": [-1]}`)
+
+	resp, err := server.Client().Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`fuel_requests_total{code="200"} 2`,
+		`fuel_requests_total{code="400"} 1`,
+		"fuel_modules_total 5\n",
+		"fuel_request_duration_seconds_count 3\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
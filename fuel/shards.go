@@ -0,0 +1,96 @@
+package fuel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileTotals are the totals of one input file of module masses.
+type FileTotals struct {
+	File    string
+	Totals  *Totals
+	Skipped int   // bad lines skipped under the Skip policy
+	Err     error // why the file couldn't be totalled, if it couldn't
+}
+
+// sumFile streams one file into its totals.
+func sumFile(ctx context.Context, fileName string, model Model, policy Policy) FileTotals {
+	result := FileTotals{File: fileName, Totals: newTotals()}
+	file, err := os.Open(fileName)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer file.Close()
+	reader := NewReader(file, policy)
+	result.Totals, result.Err = SumContext(ctx, reader, model)
+	result.Skipped = reader.Skipped
+	return result
+}
+
+// SumFiles totals every file concurrently on a pool of workers and merges
+// the files that succeeded into grand totals. Results come back in the order
+// of files whatever order the workers finish in, so the output is
+// deterministic. Cancelling ctx stops the workers, leaving the files they
+// hadn't finished with ctx's error.
+func SumFiles(ctx context.Context, files []string, model Model, policy Policy, workers int) ([]FileTotals, *Totals) {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]FileTotals, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = sumFile(ctx, files[i], model, policy)
+			}
+		}()
+	}
+	for i := range files {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			results[i] = FileTotals{File: files[i], Totals: newTotals(), Err: ctx.Err()}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	grand := newTotals()
+	for _, result := range results {
+		if result.Err == nil {
+			grand.Merge(result.Totals)
+		}
+	}
+	return results, grand
+}
+
+// Glob expands patterns into the files they match, in the order of patterns,
+// listing a file matched by more than one pattern only once so it is not
+// counted twice.
+func Glob(patterns ...string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			key, err := filepath.Abs(match)
+			if err != nil {
+				key = filepath.Clean(match)
+			}
+			if !seen[key] {
+				seen[key] = true
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
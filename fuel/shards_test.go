@@ -0,0 +1,104 @@
+package fuel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// cancellingModel is the default model, cancelling a context once it has worked out fuel a given number of times.
+type cancellingModel struct {
+	calls  *atomic.Int64
+	after  int64
+	cancel context.CancelFunc
+}
+
+func (m cancellingModel) Fuel(mass int) int {
+	if m.calls.Add(1) == m.after {
+		m.cancel()
+	}
+	return Required(mass)
+}
+
+// distinctMasses is n different masses, one per line, so none is worked out from a Memo.
+func distinctMasses(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "%d\n", 100000+i)
+	}
+	return b.String()
+}
+
+func writeMassFiles(t *testing.T, files int, content string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var names []string
+	for i := 0; i < files; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("shard%d.txt", i))
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestSumFiles(t *testing.T) {
+	files := writeMassFiles(t, 4, spacedMasses(100))
+	results, grand := SumFiles(context.Background(), files, Default, Strict, 3)
+	for i, result := range results {
+		if result.File != files[i] || result.Err != nil || result.Totals.Modules != 100 {
+			t.Errorf("result %d = %s, %d modules, %v", i, result.File, result.Totals.Modules, result.Err)
+		}
+	}
+	if grand.Modules != 400 || grand.Total.Int64() != 400*50346 {
+		t.Errorf("grand totals = %d modules, total %v", grand.Modules, grand.Total)
+	}
+}
+
+func TestSumFilesCancelled(t *testing.T) {
+	files := writeMassFiles(t, 3, distinctMasses(5*cancelCheckInterval))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Each module takes a handful of fuel calls, so this cancels partway through the first file.
+	model := cancellingModel{calls: new(atomic.Int64), after: 10 * cancelCheckInterval, cancel: cancel}
+
+	results, grand := SumFiles(ctx, files, model, Strict, 1)
+	for i, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("file %d: err = %v, want context.Canceled", i, result.Err)
+		}
+	}
+	if n := results[0].Totals.Modules; n == 0 || n >= 5*cancelCheckInterval {
+		t.Errorf("first file summed %d modules, want it stopped partway", n)
+	}
+	if grand.Modules != 0 {
+		t.Errorf("grand totals include %d modules from cancelled files", grand.Modules)
+	}
+}
+
+func TestGlobOverlapping(t *testing.T) {
+	files := writeMassFiles(t, 3, spacedMasses(10))
+	dir := filepath.Dir(files[0])
+	matched, err := Glob(filepath.Join(dir, "shard1.txt"), filepath.Join(dir, "*.txt"), filepath.Join(dir, "shard?.txt"), filepath.Join(dir, ".", "shard2.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{files[1], files[0], files[2]}
+	if !reflect.DeepEqual(matched, want) {
+		t.Fatalf("Glob = %v, want %v", matched, want)
+	}
+	_, grand := SumFiles(context.Background(), matched, Default, Strict, 2)
+	if grand.Modules != 30 {
+		t.Errorf("grand totals count %d modules, want each file's 10 once", grand.Modules)
+	}
+	if _, err := Glob("["); err == nil {
+		t.Error("want an error for a bad pattern")
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/cquon/aoc-2019
+
+go 1.23
@@ -0,0 +1,131 @@
+// Package inputs keeps puzzle inputs in a local per-user store, keyed by year
+// and day, and fetches them from a server laid out like the puzzle site.
+package inputs
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Year is the event year this repository solves.
+const Year = 2019
+
+// Store is a directory of inputs laid out as <dir>/<year>/<day>/input.txt.
+type Store struct {
+	Dir string
+}
+
+// DefaultStore is the store in $AOC_INPUT_DIR, or in the user's cache directory when that isn't set.
+func DefaultStore() (*Store, error) {
+	if dir := os.Getenv("AOC_INPUT_DIR"); dir != "" {
+		return &Store{Dir: dir}, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Dir: filepath.Join(cacheDir, "aoc-inputs")}, nil
+}
+
+// Path is where the input for year and day is kept.
+func (s *Store) Path(year, day int) string {
+	return filepath.Join(s.Dir, strconv.Itoa(year), strconv.Itoa(day), "input.txt")
+}
+
+// Has reports whether the store holds an input for year and day.
+func (s *Store) Has(year, day int) bool {
+	_, err := os.Stat(s.Path(year, day))
+	return err == nil
+}
+
+// Load returns the stored input for year and day.
+func (s *Store) Load(year, day int) (string, error) {
+	data, err := os.ReadFile(s.Path(year, day))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Save stores input for year and day, replacing any previous one.
+func (s *Store) Save(year, day int, input string) error {
+	path := s.Path(year, day)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(input), 0o600)
+}
+
+// Key identifies one stored input.
+type Key struct {
+	Year, Day int
+}
+
+// List returns the keys of every stored input, oldest year and day first.
+func (s *Store) List() ([]Key, error) {
+	paths, err := filepath.Glob(filepath.Join(s.Dir, "*", "*", "input.txt"))
+	if err != nil {
+		return nil, err
+	}
+	var keys []Key
+	for _, path := range paths {
+		dayDir := filepath.Dir(path)
+		year, yearErr := strconv.Atoi(filepath.Base(filepath.Dir(dayDir)))
+		day, dayErr := strconv.Atoi(filepath.Base(dayDir))
+		if yearErr != nil || dayErr != nil {
+			continue
+		}
+		keys = append(keys, Key{year, day})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Year != keys[j].Year {
+			return keys[i].Year < keys[j].Year
+		}
+		return keys[i].Day < keys[j].Day
+	})
+	return keys, nil
+}
+
+// Client downloads inputs from a server with the same URL layout as the puzzle
+// site, <BaseURL>/<year>/day/<day>/input, authenticated by a session cookie.
+type Client struct {
+	BaseURL string
+	Session string
+	HTTP    *http.Client // http.DefaultClient with a timeout when nil
+}
+
+// Fetch downloads the input for year and day.
+func (c *Client) Fetch(year, day int) (string, error) {
+	url := fmt.Sprintf("%s/%d/day/%d/input", strings.TrimRight(c.BaseURL, "/"), year, day)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if c.Session != "" {
+		req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	}
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,116 @@
+package inputs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	var gotPath, gotSession string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if cookie, err := r.Cookie("session"); err == nil {
+			gotSession = cookie.Value
+		}
+		w.Write([]byte("R8,U5,L5,D3\nU7,R6,D4,L4\n"))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL + "/", Session: "s3cret", HTTP: server.Client()}
+	input, err := c.Fetch(2019, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input != "R8,U5,L5,D3\nU7,R6,D4,L4\n" {
+		t.Errorf("input = %q", input)
+	}
+	if gotPath != "/2019/day/3/input" {
+		t.Errorf("requested %q, want /2019/day/3/input", gotPath)
+	}
+	if gotSession != "s3cret" {
+		t.Errorf("session cookie = %q, want s3cret", gotSession)
+	}
+}
+
+func TestFetchWithoutSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err == nil {
+			t.Error("sent a session cookie with no session set")
+		}
+		w.Write([]byte("12\n"))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, HTTP: server.Client()}
+	if _, err := c.Fetch(2019, 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFetchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Please log in", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Session: "expired", HTTP: server.Client()}
+	_, err := c.Fetch(2019, 4)
+	if err == nil {
+		t.Fatal("want an error for a 400 response")
+	}
+	if !strings.Contains(err.Error(), "/2019/day/4/input") || !strings.Contains(err.Error(), "400") {
+		t.Errorf("error %q should name the URL and status", err)
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	if s.Has(2019, 3) {
+		t.Fatal("empty store has day 3")
+	}
+	if _, err := s.Load(2019, 3); err == nil {
+		t.Fatal("loading a missing input should fail")
+	}
+
+	for _, k := range []Key{{2019, 12}, {2019, 3}, {2018, 25}} {
+		if err := s.Save(k.Year, k.Day, "old"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Save(2019, 3, "R8,U5\n"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Has(2019, 3) {
+		t.Fatal("store should have day 3 after saving it")
+	}
+	input, err := s.Load(2019, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input != "R8,U5\n" {
+		t.Errorf("Load = %q, want the replaced input", input)
+	}
+	if want := filepath.Join(s.Dir, "2019", "3", "input.txt"); s.Path(2019, 3) != want {
+		t.Errorf("Path = %q, want %q", s.Path(2019, 3), want)
+	}
+
+	// Directories that aren't years and days are ignored.
+	if err := os.MkdirAll(filepath.Join(s.Dir, "notes", "x"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, "notes", "x", "input.txt"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Key{{2018, 25}, {2019, 3}, {2019, 12}}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("List = %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,99 @@
+package solution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// Answers are the accepted answers for each day and part, loaded from a JSON file like
+//
+//	{"1": {"1": "3404722", "2": "5104215"}}
+type Answers map[string]map[string]string
+
+// LoadAnswers reads an answers file. A missing file gives no answers rather than an error.
+func LoadAnswers(fileName string) (Answers, error) {
+	answers := make(Answers)
+	data, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return answers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &answers); err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return answers, nil
+}
+
+// Expected returns the accepted answer for a day and part.
+func (a Answers) Expected(day, part int) (string, bool) {
+	want, ok := a[strconv.Itoa(day)][strconv.Itoa(part)]
+	return want, ok
+}
+
+// Result is the outcome of solving one part of one day.
+type Result struct {
+	Day, Part int
+	Got       string
+	Want      string // empty when there is no accepted answer yet
+	Err       error
+}
+
+// Status is "ok" when the answer matches, "unverified" when there is no accepted
+// answer to compare against, and "REGRESSION" or "ERROR" otherwise.
+func (r Result) Status() string {
+	switch {
+	case r.Err != nil:
+		return "ERROR"
+	case r.Want == "":
+		return "unverified"
+	case r.Got == r.Want:
+		return "ok"
+	}
+	return "REGRESSION"
+}
+
+// Failed reports whether the result errored or disagrees with the accepted answer.
+func (r Result) Failed() bool {
+	return r.Status() == "ERROR" || r.Status() == "REGRESSION"
+}
+
+// Verify solves both parts of day and compares them against answers.
+func Verify(day int, input string, answers Answers) ([]Result, error) {
+	s, ok := Lookup(day)
+	if !ok {
+		return nil, fmt.Errorf("no solution registered for day %d", day)
+	}
+	var results []Result
+	for part := 1; part <= 2; part++ {
+		r := Result{Day: day, Part: part}
+		r.Got, r.Err = Solve(s, part, input)
+		r.Want, _ = answers.Expected(day, part)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// Report prints results in the "Part N: answer" form every day uses, flagging any that
+// don't match their accepted answer, and reports whether all of them passed.
+func Report(w io.Writer, results []Result) bool {
+	passed := true
+	for _, r := range results {
+		switch r.Status() {
+		case "ERROR":
+			fmt.Fprintf(w, "Part %d: error: %v\n", r.Part, r.Err)
+		case "REGRESSION":
+			fmt.Fprintf(w, "Part %d: %s (REGRESSION, expected %s)\n", r.Part, r.Got, r.Want)
+		default:
+			fmt.Fprintf(w, "Part %d: %s\n", r.Part, r.Got)
+		}
+		if r.Failed() {
+			passed = false
+		}
+	}
+	return passed
+}
@@ -0,0 +1,70 @@
+// Package solution is the common interface every day's puzzle solution implements,
+// and the registry days add themselves to.
+package solution
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Solution solves both parts of one day's puzzle from the raw puzzle input.
+type Solution interface {
+	Part1(input string) (string, error)
+	Part2(input string) (string, error)
+}
+
+var registry = make(map[int]Solution)
+
+// Register makes s the solution for day. Registering a day twice panics.
+func Register(day int, s Solution) {
+	if _, exists := registry[day]; exists {
+		panic(fmt.Sprintf("solution: day %d registered twice", day))
+	}
+	registry[day] = s
+}
+
+// Lookup returns the solution registered for day.
+func Lookup(day int) (Solution, bool) {
+	s, ok := registry[day]
+	return s, ok
+}
+
+// Days returns every registered day in order.
+func Days() []int {
+	var days []int
+	for day := range registry {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
+
+// Solve runs one part (1 or 2) of s on input.
+func Solve(s Solution, part int, input string) (string, error) {
+	switch part {
+	case 1:
+		return s.Part1(input)
+	case 2:
+		return s.Part2(input)
+	}
+	return "", fmt.Errorf("no part %d, want 1 or 2", part)
+}
+
+// Validator is implemented by solutions that can check an input is well formed before solving it.
+type Validator interface {
+	Validate(input string) error
+}
+
+// Validate checks input against the format checks of day's solution, if it has any.
+func Validate(day int, input string) error {
+	s, ok := Lookup(day)
+	if !ok {
+		return fmt.Errorf("no solution registered for day %d", day)
+	}
+	if v, ok := s.(Validator); ok {
+		if err := v.Validate(input); err != nil {
+			return fmt.Errorf("day %d input: %v", day, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package wire
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Visits are the steps at which a wire enters each cell it covers, in order,
+// so a cell the wire loops back through has more than one.
+func Visits(path Path, costs Costs) map[Point][]int {
+	visits := make(map[Point][]int)
+	for cell, steps := range path.Cells(costs) {
+		visits[cell] = append(visits[cell], steps)
+	}
+	return visits
+}
+
+// DelayRule is how the signal delay to a cell is counted when a wire visits it more than once.
+type DelayRule int
+
+const (
+	// FirstVisit counts the steps to the wire's first visit, as the puzzle does.
+	FirstVisit DelayRule = iota
+	// LastVisit counts the steps to the wire's last visit.
+	LastVisit
+	// LoopFree counts the steps along the wire with every loop it makes on the way cut out.
+	LoopFree
+)
+
+var delayRuleNames = []string{"first", "last", "loopfree"}
+
+func (r DelayRule) String() string {
+	if r < 0 || int(r) >= len(delayRuleNames) {
+		return fmt.Sprintf("DelayRule(%d)", int(r))
+	}
+	return delayRuleNames[r]
+}
+
+// ParseDelayRule parses "first", "last" or "loopfree".
+func ParseDelayRule(name string) (DelayRule, error) {
+	for i, n := range delayRuleNames {
+		if n == name {
+			return DelayRule(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown delay rule %q, want first, last or loopfree", name)
+}
+
+// Delays is the signal delay to every cell the wire covers under rule.
+func Delays(path Path, costs Costs, rule DelayRule) map[Point]int {
+	delays := make(map[Point]int)
+	loopFree, last := 0, 0
+	for cell, steps := range path.Cells(costs) {
+		switch rule {
+		case FirstVisit:
+			if _, ok := delays[cell]; !ok {
+				delays[cell] = steps
+			}
+		case LastVisit:
+			delays[cell] = steps
+		case LoopFree:
+			loopFree += steps - last
+			if earlier, ok := delays[cell]; ok {
+				loopFree = min(loopFree, earlier)
+			}
+			if cell == (Point{}) {
+				loopFree = 0
+			}
+			delays[cell] = loopFree
+		}
+		last = steps
+	}
+	return delays
+}
+
+// DelayCrossings are the cells, other than the central port, both wires reach,
+// with the delays a and b give them, fewest combined steps first.
+func DelayCrossings(a, b map[Point]int) []Crossing {
+	var cs []Crossing
+	for p, stepsA := range a {
+		if stepsB, ok := b[p]; ok && p != (Point{}) {
+			cs = append(cs, Crossing{p, stepsA, stepsB})
+		}
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].Steps() != cs[j].Steps() {
+			return cs[i].Steps() < cs[j].Steps()
+		}
+		if cs[i].Point.X != cs[j].Point.X {
+			return cs[i].Point.X < cs[j].Point.X
+		}
+		return cs[i].Point.Y < cs[j].Point.Y
+	})
+	return cs
+}
+
+// Reached is the tick at which signals sent down both wires at tick 0, moving
+// one step a tick, have both reached the crossing.
+func (c Crossing) Reached() int {
+	return max(c.StepsA, c.StepsB)
+}
+
+// Timeline orders crossings by the tick both signals reach them.
+func Timeline(cs []Crossing) []Crossing {
+	timeline := append([]Crossing(nil), cs...)
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Reached() < timeline[j].Reached()
+	})
+	return timeline
+}
+
+// ReachedBy is the crossings both signals have reached by tick t, in the order they were reached.
+func ReachedBy(cs []Crossing, t int) []Crossing {
+	timeline := Timeline(cs)
+	n := sort.Search(len(timeline), func(i int) bool {
+		return timeline[i].Reached() > t
+	})
+	return timeline[:n]
+}
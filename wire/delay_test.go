@@ -0,0 +1,99 @@
+package wire
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDelays(t *testing.T) {
+	for _, test := range []struct {
+		path  string
+		costs Costs
+		rule  DelayRule
+		want  map[Point]int
+	}{
+		// A loop back through 2,0, then on down.
+		{"R4,U2,L2,D4", nil, FirstVisit, map[Point]int{{2, 0}: 2, {4, 2}: 6, {2, 1}: 9, {2, -1}: 11, {2, -2}: 12}},
+		{"R4,U2,L2,D4", nil, LastVisit, map[Point]int{{2, 0}: 10, {4, 2}: 6, {2, 1}: 9, {2, -1}: 11, {2, -2}: 12}},
+		{"R4,U2,L2,D4", nil, LoopFree, map[Point]int{{2, 0}: 2, {4, 2}: 6, {2, 1}: 9, {2, -1}: 3, {2, -2}: 4}},
+		// Back through the central port, which cuts everything before it.
+		{"R2,L4", nil, FirstVisit, map[Point]int{{1, 0}: 1, {0, 0}: 4, {-1, 0}: 5, {-2, 0}: 6}},
+		{"R2,L4", nil, LoopFree, map[Point]int{{1, 0}: 1, {0, 0}: 0, {-1, 0}: 1, {-2, 0}: 2}},
+		// A diagonal loop that costs more than it saves.
+		{"R3,UL1,DL1,R3", Costs{"UL": 3}, FirstVisit, map[Point]int{{2, 1}: 6, {1, 0}: 1, {4, 0}: 10}},
+		{"R3,UL1,DL1,R3", Costs{"UL": 3}, LastVisit, map[Point]int{{2, 1}: 6, {1, 0}: 7, {4, 0}: 10}},
+		{"R3,UL1,DL1,R3", Costs{"UL": 3}, LoopFree, map[Point]int{{2, 1}: 6, {1, 0}: 1, {4, 0}: 4}},
+	} {
+		paths := mustParse(t, test.path)
+		delays := Delays(paths[0], test.costs, test.rule)
+		for p, want := range test.want {
+			if got, ok := delays[p]; !ok || got != want {
+				t.Errorf("Delays(%s, %v, %s) at %v = %d, want %d", test.path, test.costs, test.rule, p, got, want)
+			}
+		}
+		if visits := Visits(paths[0], test.costs); len(delays) != len(visits) {
+			t.Errorf("Delays(%s, %v, %s) covers %d cells, want %d", test.path, test.costs, test.rule, len(delays), len(visits))
+		}
+	}
+}
+
+func TestDelayCrossings(t *testing.T) {
+	paths := mustParse(t, "R4,U2,L2,D4", "D2,R2,U3")
+	for _, test := range []struct {
+		rule DelayRule
+		want []Crossing
+	}{
+		{FirstVisit, []Crossing{{Point{2, 0}, 2, 6}, {Point{2, -2}, 12, 4}, {Point{2, -1}, 11, 5}, {Point{2, 1}, 9, 7}}},
+		{LoopFree, []Crossing{{Point{2, -2}, 4, 4}, {Point{2, -1}, 3, 5}, {Point{2, 0}, 2, 6}, {Point{2, 1}, 9, 7}}},
+	} {
+		got := DelayCrossings(Delays(paths[0], nil, test.rule), Delays(paths[1], nil, test.rule))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("DelayCrossings under %s = %v, want %v", test.rule, got, test.want)
+		}
+	}
+}
+
+func TestReachedBy(t *testing.T) {
+	cs := []Crossing{
+		{Point{3, 3}, 20, 20},
+		{Point{6, 5}, 15, 15},
+		{Point{-2, 4}, 5, 25},
+		{Point{1, -1}, 12, 3},
+		{Point{7, 0}, 20, 1},
+	}
+	for _, test := range []struct {
+		tick int
+		want []Point
+	}{
+		{11, nil},
+		{12, []Point{{1, -1}}},
+		{19, []Point{{1, -1}, {6, 5}}},
+		{20, []Point{{1, -1}, {6, 5}, {3, 3}, {7, 0}}},
+		{100, []Point{{1, -1}, {6, 5}, {3, 3}, {7, 0}, {-2, 4}}},
+	} {
+		var got []Point
+		for _, c := range ReachedBy(cs, test.tick) {
+			got = append(got, c.Point)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ReachedBy(%d) = %v, want %v", test.tick, got, test.want)
+		}
+	}
+	if cs[0].Point != (Point{3, 3}) {
+		t.Errorf("ReachedBy reordered its argument")
+	}
+}
+
+func TestParseDelayRule(t *testing.T) {
+	for _, rule := range []DelayRule{FirstVisit, LastVisit, LoopFree} {
+		if got, err := ParseDelayRule(rule.String()); err != nil || got != rule {
+			t.Errorf("ParseDelayRule(%s) = %v, %v", rule, got, err)
+		}
+	}
+	if _, err := ParseDelayRule("Loop-free"); err == nil {
+		t.Errorf("ParseDelayRule accepted an unknown rule")
+	}
+	if got := DelayRule(7).String(); got != "DelayRule(7)" {
+		t.Errorf("String of an unknown rule = %s", got)
+	}
+}
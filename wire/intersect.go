@@ -0,0 +1,259 @@
+package wire
+
+import (
+	"sort"
+)
+
+// Crossing is a cell two wires both pass through, with the fewest steps each
+// wire takes to first reach it.
+type Crossing struct {
+	Point  Point
+	StepsA int
+	StepsB int
+}
+
+// Steps is the combined signal delay of both wires at the crossing.
+func (c Crossing) Steps() int {
+	return c.StepsA + c.StepsB
+}
+
+// overlap calls visit with the parameters ta and tb of every cell segments a
+// and b share, so that a.At(ta) == b.At(tb).
+func overlap(a, b Segment, visit func(ta, tb int)) {
+	offset := b.Start.Sub(a.Start)
+	if den := a.Delta.cross(b.Delta); den != 0 {
+		// a.Start + ta*a.Delta == b.Start + tb*b.Delta
+		numA := offset.cross(b.Delta)
+		numB := offset.cross(a.Delta)
+		if numA%den != 0 || numB%den != 0 {
+			return
+		}
+		ta, tb := numA/den, numB/den
+		if ta >= 1 && ta <= a.Length && tb >= 1 && tb <= b.Length {
+			visit(ta, tb)
+		}
+		return
+	}
+	if offset.cross(a.Delta) != 0 {
+		return // parallel but not on the same line
+	}
+	// Collinear: tb = c + sign*ta for every cell of a on b's line.
+	n := b.Delta.dot(b.Delta)
+	c := -offset.dot(b.Delta)
+	if c%n != 0 {
+		return
+	}
+	c /= n
+	sign := a.Delta.dot(b.Delta) / n
+	lo, hi := 1, a.Length
+	if sign > 0 {
+		lo, hi = max(lo, 1-c), min(hi, b.Length-c)
+	} else {
+		lo, hi = max(lo, c-b.Length), min(hi, c-1)
+	}
+	for ta := lo; ta <= hi; ta++ {
+		visit(ta, c+sign*ta)
+	}
+}
+
+// crossings collects the cells where the segments meet, keeping the fewest
+// steps per wire and leaving out the central port.
+type crossings map[Point]Crossing
+
+func (cs crossings) add(a, b Segment) {
+	overlap(a, b, func(ta, tb int) {
+		p := a.At(ta)
+		if p == (Point{}) {
+			return
+		}
+		stepsA, stepsB := a.StepsAt(ta), b.StepsAt(tb)
+		if c, seen := cs[p]; seen {
+			stepsA, stepsB = min(stepsA, c.StepsA), min(stepsB, c.StepsB)
+		}
+		cs[p] = Crossing{p, stepsA, stepsB}
+	})
+}
+
+func (cs crossings) sorted() []Crossing {
+	list := make([]Crossing, 0, len(cs))
+	for _, c := range cs {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Point.X != list[j].Point.X {
+			return list[i].Point.X < list[j].Point.X
+		}
+		return list[i].Point.Y < list[j].Point.Y
+	})
+	return list
+}
+
+// sweepThreshold is the number of segment pairs above which Intersect uses a sweep line.
+const sweepThreshold = 1 << 12
+
+// Intersect returns every cell wires a and b both pass through apart from the
+// central port, ordered by x then y. Small inputs test every pair of
+// segments; large ones sweep a line across the grid.
+func Intersect(a, b []Segment) []Crossing {
+	cs := make(crossings)
+	if len(a)*len(b) <= sweepThreshold {
+		for _, sa := range a {
+			for _, sb := range b {
+				cs.add(sa, sb)
+			}
+		}
+		return cs.sorted()
+	}
+	sweep(a, b, cs)
+	return cs.sorted()
+}
+
+func horizontal(s Segment) bool {
+	return s.Delta.Y == 0 && s.Delta.X != 0
+}
+
+func vertical(s Segment) bool {
+	return s.Delta.X == 0 && s.Delta.Y != 0
+}
+
+// sweep finds the crossings of a and b. Horizontal segments of one wire are
+// checked against vertical segments of the other by sweeping a vertical line
+// left to right over an active set ordered by y. Segments on the same line are
+// checked against the others on that line, and any other segments pairwise.
+func sweep(a, b []Segment, cs crossings) {
+	sweepPerpendicular(a, b, cs, false)
+	sweepPerpendicular(b, a, cs, true)
+
+	type line struct {
+		horizontal bool
+		at         int
+	}
+	lines := make(map[line][2][]Segment)
+	var otherA, otherB []Segment
+	for i, wire := range [][]Segment{a, b} {
+		for _, s := range wire {
+			var l line
+			switch {
+			case horizontal(s):
+				l = line{true, s.Start.Y}
+			case vertical(s):
+				l = line{false, s.Start.X}
+			default:
+				if i == 0 {
+					otherA = append(otherA, s)
+				} else {
+					otherB = append(otherB, s)
+				}
+				continue
+			}
+			segs := lines[l]
+			segs[i] = append(segs[i], s)
+			lines[l] = segs
+		}
+	}
+	for _, segs := range lines {
+		for _, sa := range segs[0] {
+			for _, sb := range segs[1] {
+				cs.add(sa, sb)
+			}
+		}
+	}
+	for _, sa := range otherA {
+		for _, sb := range b {
+			cs.add(sa, sb)
+		}
+	}
+	for _, sb := range otherB {
+		for _, sa := range a {
+			if horizontal(sa) || vertical(sa) {
+				cs.add(sa, sb)
+			}
+		}
+	}
+}
+
+// sweepPerpendicular finds where horizontal segments of h cross vertical
+// segments of v. swapped says h is wire b, so crossings are recorded the right way round.
+func sweepPerpendicular(h, v []Segment, cs crossings, swapped bool) {
+	type event struct {
+		x    int
+		kind int // 0 adds a horizontal segment, 1 queries a vertical one, 2 removes a horizontal one
+		seg  Segment
+	}
+	var events []event
+	for _, s := range h {
+		if horizontal(s) {
+			x1, x2 := s.At(1).X, s.End().X
+			events = append(events, event{min(x1, x2), 0, s}, event{max(x1, x2), 2, s})
+		}
+	}
+	for _, s := range v {
+		if vertical(s) {
+			events = append(events, event{s.Start.X, 1, s})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].x != events[j].x {
+			return events[i].x < events[j].x
+		}
+		return events[i].kind < events[j].kind
+	})
+
+	var active []Segment // ordered by y
+	search := func(y int) int {
+		return sort.Search(len(active), func(i int) bool { return active[i].Start.Y >= y })
+	}
+	for _, e := range events {
+		switch e.kind {
+		case 0:
+			i := search(e.seg.Start.Y)
+			active = append(active, Segment{})
+			copy(active[i+1:], active[i:])
+			active[i] = e.seg
+		case 2:
+			for i := search(e.seg.Start.Y); i < len(active); i++ {
+				if active[i] == e.seg {
+					active = append(active[:i], active[i+1:]...)
+					break
+				}
+			}
+		case 1:
+			y1, y2 := e.seg.At(1).Y, e.seg.End().Y
+			for i := search(min(y1, y2)); i < len(active) && active[i].Start.Y <= max(y1, y2); i++ {
+				if swapped {
+					cs.add(e.seg, active[i])
+				} else {
+					cs.add(active[i], e.seg)
+				}
+			}
+		}
+	}
+}
+
+// Closest is the crossing nearest the central port by Manhattan distance.
+func Closest(cs []Crossing) (Crossing, bool) {
+	if len(cs) == 0 {
+		return Crossing{}, false
+	}
+	best := cs[0]
+	for _, c := range cs[1:] {
+		if c.Point.Manhattan() < best.Point.Manhattan() {
+			best = c
+		}
+	}
+	return best, true
+}
+
+// Fewest is the crossing with the fewest combined steps.
+func Fewest(cs []Crossing) (Crossing, bool) {
+	if len(cs) == 0 {
+		return Crossing{}, false
+	}
+	best := cs[0]
+	for _, c := range cs[1:] {
+		if c.Steps() < best.Steps() {
+			best = c
+		}
+	}
+	return best, true
+}
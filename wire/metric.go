@@ -0,0 +1,95 @@
+package wire
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Metric is a distance between two cells.
+type Metric func(p, q Point) float64
+
+// Manhattan is the distance moving only along the grid.
+func Manhattan(p, q Point) float64 {
+	return float64(p.Sub(q).Manhattan())
+}
+
+// Chebyshev is the distance moving along the grid or diagonally.
+func Chebyshev(p, q Point) float64 {
+	d := p.Sub(q)
+	return float64(max(abs(d.X), abs(d.Y)))
+}
+
+// Euclidean is the straight line distance.
+func Euclidean(p, q Point) float64 {
+	d := p.Sub(q)
+	return math.Hypot(float64(d.X), float64(d.Y))
+}
+
+// metrics are the metrics that can be chosen by name, lower case.
+var metrics = map[string]Metric{
+	"manhattan": Manhattan,
+	"chebyshev": Chebyshev,
+	"euclidean": Euclidean,
+}
+
+// RegisterMetric makes m the metric called name, in any case, so that
+// LookupMetric and the wires query tool's -metric flag accept it. Registering
+// a name twice panics.
+func RegisterMetric(name string, m Metric) {
+	key := strings.ToLower(name)
+	if _, exists := metrics[key]; exists {
+		panic(fmt.Sprintf("wire: metric %q registered twice", name))
+	}
+	metrics[key] = m
+}
+
+// LookupMetric is the metric called name, in any case.
+func LookupMetric(name string) (Metric, error) {
+	if metric, ok := metrics[strings.ToLower(name)]; ok {
+		return metric, nil
+	}
+	return nil, fmt.Errorf("unknown metric %q, want one of %s", name, strings.Join(MetricNames(), ", "))
+}
+
+// MetricNames are the names of the registered metrics, sorted.
+func MetricNames() []string {
+	var names []string
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ranked is a crossing and its distance from a reference point.
+type Ranked struct {
+	Crossing
+	Distance float64
+}
+
+// Rank orders crossings by their distance from ref, nearest first and ties
+// broken by fewest steps then position, and keeps the first k (all if k <= 0).
+func Rank(cs []Crossing, metric Metric, ref Point, k int) []Ranked {
+	ranked := make([]Ranked, len(cs))
+	for i, c := range cs {
+		ranked[i] = Ranked{c, metric(c.Point, ref)}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		switch {
+		case a.Distance != b.Distance:
+			return a.Distance < b.Distance
+		case a.Steps() != b.Steps():
+			return a.Steps() < b.Steps()
+		case a.Point.X != b.Point.X:
+			return a.Point.X < b.Point.X
+		}
+		return a.Point.Y < b.Point.Y
+	})
+	if k > 0 && k < len(ranked) {
+		ranked = ranked[:k]
+	}
+	return ranked
+}
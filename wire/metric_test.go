@@ -0,0 +1,51 @@
+package wire
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCustomMetric(t *testing.T) {
+	// Distance along x only, so crossings at the same x tie and fall back to steps.
+	RegisterMetric("Horizontal", func(p, q Point) float64 { return float64(abs(p.X - q.X)) })
+	t.Cleanup(func() { delete(metrics, "horizontal") })
+
+	metric, err := LookupMetric("HORIZONTAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := []Crossing{
+		{Point: Point{3, 0}, StepsA: 3, StepsB: 3},
+		{Point: Point{1, 9}, StepsA: 20, StepsB: 20},
+		{Point: Point{1, 5}, StepsA: 10, StepsB: 10},
+	}
+	ranked := Rank(cs, metric, Point{}, 2)
+	if len(ranked) != 2 || ranked[0].Point != (Point{1, 5}) || ranked[1].Point != (Point{1, 9}) {
+		t.Errorf("Rank = %v, want {1 5} then {1 9}", ranked)
+	}
+	if names := strings.Join(MetricNames(), ","); names != "chebyshev,euclidean,horizontal,manhattan" {
+		t.Errorf("MetricNames = %s", names)
+	}
+}
+
+func TestRegisterMetricTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering Manhattan over manhattan did not panic")
+		}
+		if names := strings.Join(MetricNames(), ","); names != "chebyshev,euclidean,manhattan" {
+			t.Errorf("MetricNames = %s", names)
+		}
+	}()
+	RegisterMetric("Manhattan", Chebyshev)
+}
+
+func TestLookupMetric(t *testing.T) {
+	metric, err := LookupMetric("Chebyshev")
+	if err != nil || metric(Point{3, -7}, Point{}) != 7 {
+		t.Errorf("LookupMetric(Chebyshev) = %v", err)
+	}
+	if _, err := LookupMetric("taxicab"); err == nil || err.Error() != `unknown metric "taxicab", want one of chebyshev, euclidean, manhattan` {
+		t.Errorf("LookupMetric(taxicab) error = %v", err)
+	}
+}
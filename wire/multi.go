@@ -0,0 +1,119 @@
+package wire
+
+import "sort"
+
+// PairCrossings are the crossings between wires A and B, indexes into the list of wires.
+type PairCrossings struct {
+	A, B      int
+	Crossings []Crossing
+}
+
+// Pairwise intersects every pair of wires, with some step costs overridden.
+func Pairwise(paths []Path, costs Costs) []PairCrossings {
+	segments := make([][]Segment, len(paths))
+	for i, path := range paths {
+		segments[i] = path.SegmentsWithCosts(costs)
+	}
+	var pairs []PairCrossings
+	for i := range segments {
+		for j := i + 1; j < len(segments); j++ {
+			pairs = append(pairs, PairCrossings{i, j, Intersect(segments[i], segments[j])})
+		}
+	}
+	return pairs
+}
+
+// MultiCrossing is a cell crossed by several wires, with the fewest steps each takes to reach it.
+type MultiCrossing struct {
+	Point Point
+	Wires []int // indexes of the wires, in order
+	Steps []int // Steps[i] is the steps wire Wires[i] takes to first reach Point
+}
+
+// CrossedBy returns the cells, apart from the central port, that at least k
+// of the wires pass through, ordered by how many wires then by x and y. k must be at least 2.
+func CrossedBy(pairs []PairCrossings, k int) []MultiCrossing {
+	steps := make(map[Point]map[int]int)
+	record := func(p Point, wire, s int) {
+		if steps[p] == nil {
+			steps[p] = make(map[int]int)
+		}
+		steps[p][wire] = s
+	}
+	for _, pair := range pairs {
+		for _, c := range pair.Crossings {
+			record(c.Point, pair.A, c.StepsA)
+			record(c.Point, pair.B, c.StepsB)
+		}
+	}
+	var result []MultiCrossing
+	for p, byWire := range steps {
+		if len(byWire) < max(k, 2) {
+			continue
+		}
+		mc := MultiCrossing{Point: p}
+		for wire := range byWire {
+			mc.Wires = append(mc.Wires, wire)
+		}
+		sort.Ints(mc.Wires)
+		for _, wire := range mc.Wires {
+			mc.Steps = append(mc.Steps, byWire[wire])
+		}
+		result = append(result, mc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if len(a.Wires) != len(b.Wires) {
+			return len(a.Wires) > len(b.Wires)
+		}
+		if a.Point.X != b.Point.X {
+			return a.Point.X < b.Point.X
+		}
+		return a.Point.Y < b.Point.Y
+	})
+	return result
+}
+
+// SelfCrossing is a cell one wire passes through more than once.
+type SelfCrossing struct {
+	Point Point
+	Steps []int // the steps at each visit, in order
+}
+
+// SelfCrossings returns every cell the wire visits more than once, ordered by first visit.
+// The central port only counts if the wire comes back through it.
+func SelfCrossings(path Path, costs Costs) []SelfCrossing {
+	segments := path.SegmentsWithCosts(costs)
+	visits := make(map[Point]map[int]bool)
+	record := func(p Point, s int) {
+		if visits[p] == nil {
+			visits[p] = make(map[int]bool)
+		}
+		visits[p][s] = true
+	}
+	for _, s := range segments {
+		if t, ok := s.Find(Point{}); ok {
+			record(Point{}, 0)
+			record(Point{}, s.StepsAt(t))
+		}
+	}
+	for i, a := range segments {
+		for _, b := range segments[i+1:] {
+			overlap(a, b, func(ta, tb int) {
+				record(a.At(ta), a.StepsAt(ta))
+				record(b.At(tb), b.StepsAt(tb))
+			})
+		}
+	}
+	var result []SelfCrossing
+	for p, steps := range visits {
+		sc := SelfCrossing{Point: p}
+		for s := range steps {
+			sc.Steps = append(sc.Steps, s)
+		}
+		sort.Ints(sc.Steps)
+		result = append(result, sc)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Steps[0] < result[j].Steps[0] })
+	return result
+}
@@ -0,0 +1,66 @@
+package wire
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mustParse(t *testing.T, lines ...string) []Path {
+	t.Helper()
+	var paths []Path
+	for i, line := range lines {
+		path, err := Parser{Strict: true}.ParseWire(i+1, line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestCrossedBy(t *testing.T) {
+	paths := mustParse(t, "R8,U5,L5,D3", "U7,R6,D4,L4", "D1,R3,U4")
+	pairs := Pairwise(paths, nil)
+	if len(pairs) != 3 || pairs[0].A != 0 || pairs[0].B != 1 || pairs[2].A != 1 || pairs[2].B != 2 {
+		t.Fatalf("Pairwise paired %+v", pairs)
+	}
+
+	want := []MultiCrossing{
+		{Point{3, 3}, []int{0, 1, 2}, []int{20, 20, 8}},
+		{Point{3, 0}, []int{0, 2}, []int{3, 5}},
+		{Point{3, 2}, []int{0, 2}, []int{21, 7}},
+		{Point{6, 5}, []int{0, 1}, []int{15, 15}},
+	}
+	if got := CrossedBy(pairs, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("CrossedBy(2) = %v, want %v", got, want)
+	}
+	if got := CrossedBy(pairs, 3); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("CrossedBy(3) = %v, want %v", got, want[:1])
+	}
+	// k below 2 still needs two wires.
+	if got := CrossedBy(pairs, 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("CrossedBy(1) = %v, want %v", got, want)
+	}
+	if got := CrossedBy(pairs, 4); len(got) != 0 {
+		t.Errorf("CrossedBy(4) = %v, want none", got)
+	}
+}
+
+func TestSelfCrossings(t *testing.T) {
+	for _, test := range []struct {
+		path  string
+		costs Costs
+		want  []SelfCrossing
+	}{
+		{"R2,U2,L1,D3", nil, []SelfCrossing{{Point{1, 0}, []int{1, 7}}}},
+		{"R1,L2", nil, []SelfCrossing{{Point{}, []int{0, 2}}}},
+		{"R3,L2", nil, []SelfCrossing{{Point{1, 0}, []int{1, 5}}, {Point{2, 0}, []int{2, 4}}}},
+		{"R3,L2", Costs{"L": 2}, []SelfCrossing{{Point{1, 0}, []int{1, 7}}, {Point{2, 0}, []int{2, 5}}}},
+		{"R8,U5,L5,D3", nil, nil},
+	} {
+		got := SelfCrossings(mustParse(t, test.path)[0], test.costs)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SelfCrossings(%s, %v) = %v, want %v", test.path, test.costs, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Package wire models the wires of day 3 as straight segments rather than the
+// grid cells they cover, and finds where wires cross.
+package wire
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Point is a grid cell. The central port is the origin, x grows to the right and y upwards.
+type Point struct {
+	X, Y int
+}
+
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Scale multiplies both coordinates by k.
+func (p Point) Scale(k int) Point {
+	return Point{p.X * k, p.Y * k}
+}
+
+func (p Point) dot(q Point) int {
+	return p.X*q.X + p.Y*q.Y
+}
+
+func (p Point) cross(q Point) int {
+	return p.X*q.Y - p.Y*q.X
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Manhattan is the Manhattan distance of p from the origin.
+func (p Point) Manhattan() int {
+	return abs(p.X) + abs(p.Y)
+}
+
+// Direction is a direction a wire can run in, moving by Delta per cell and
+// taking Cost steps to enter each cell.
+type Direction struct {
+	Name  string
+	Delta Point
+	Cost  int
+}
+
+// Directions are the directions wires can take, by the letters used in paths.
+var Directions = map[string]Direction{
+	"L":  {"L", Point{-1, 0}, 1},
+	"R":  {"R", Point{1, 0}, 1},
+	"U":  {"U", Point{0, 1}, 1},
+	"D":  {"D", Point{0, -1}, 1},
+	"UL": {"UL", Point{-1, 1}, 1},
+	"UR": {"UR", Point{1, 1}, 1},
+	"DL": {"DL", Point{-1, -1}, 1},
+	"DR": {"DR", Point{1, -1}, 1},
+}
+
+// Costs overrides the steps it takes to enter a cell in some directions, by direction name.
+type Costs map[string]int
+
+// cost is the steps to enter a cell moving in dir.
+func (c Costs) cost(dir Direction) int {
+	if cost, ok := c[dir.Name]; ok {
+		return cost
+	}
+	return dir.Cost
+}
+
+// ParseCosts parses step costs such as "UL=2,UR=2".
+func ParseCosts(text string) (Costs, error) {
+	costs := make(Costs)
+	if strings.TrimSpace(text) == "" {
+		return costs, nil
+	}
+	for _, field := range strings.Split(text, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(field), "=")
+		if _, ok := Directions[name]; !ok || !found {
+			return nil, fmt.Errorf("step cost %q must look like UL=2", field)
+		}
+		cost, err := strconv.Atoi(value)
+		if err != nil || cost < 1 {
+			return nil, fmt.Errorf("step cost %q must be a positive integer", field)
+		}
+		costs[name] = cost
+	}
+	return costs, nil
+}
+
+// Move is one step of a path such as "R8": a direction and a number of cells.
+type Move struct {
+	Direction Direction
+	Length    int
+}
+
+func (m Move) String() string {
+	return m.Direction.Name + strconv.Itoa(m.Length)
+}
+
+// Path is the moves a wire makes out of the central port.
+type Path []Move
+
+func (p Path) String() string {
+	moves := make([]string, len(p))
+	for i, m := range p {
+		moves[i] = m.String()
+	}
+	return strings.Join(moves, ",")
+}
+
+// Errors a ParseError can wrap.
+var (
+	ErrEmpty     = errors.New("empty move")
+	ErrDirection = errors.New("unknown direction, want L|D|U|R|UL|UR|DL|DR")
+	ErrLength    = errors.New("length is not a number")
+	ErrNegative  = errors.New("length must be positive")
+)
+
+// ParseError is a move that could not be parsed. Wire and Token count from 1
+// and are 0 when unknown, Column is the byte offset of the move in its line.
+type ParseError struct {
+	Wire   int
+	Token  int
+	Column int
+	Text   string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	var where []string
+	if e.Wire > 0 {
+		where = append(where, fmt.Sprintf("wire %d", e.Wire))
+	}
+	if e.Token > 0 {
+		where = append(where, fmt.Sprintf("move %d (column %d)", e.Token, e.Column+1))
+	}
+	where = append(where, strconv.Quote(e.Text))
+	return strings.Join(where, ", ") + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parser parses wire paths. Directions may be lower case and moves may be
+// padded with spaces. The zero Parser is lenient: it skips empty moves,
+// allows zero lengths and runs negative lengths the opposite way. A Strict
+// parser rejects all three.
+type Parser struct {
+	Strict bool
+}
+
+// ParseMove parses a single move such as "R8".
+func (p Parser) ParseMove(token string) (Move, error) {
+	text := strings.TrimSpace(token)
+	fail := func(err error) (Move, error) {
+		return Move{}, &ParseError{Text: token, Err: err}
+	}
+	if text == "" {
+		return fail(ErrEmpty)
+	}
+	for n := len(text); n > 0; n-- {
+		dir, ok := Directions[strings.ToUpper(text[:n])]
+		if !ok {
+			continue
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(text[n:]))
+		if err != nil {
+			return fail(ErrLength)
+		}
+		if length < 0 || (length == 0 && p.Strict) {
+			if p.Strict {
+				return fail(ErrNegative)
+			}
+			if length == math.MinInt {
+				return fail(ErrLength) // can't be run the other way
+			}
+			dir, length = reverse(dir), -length
+		}
+		return Move{dir, length}, nil
+	}
+	return fail(ErrDirection)
+}
+
+// reverse is the direction opposite dir.
+func reverse(dir Direction) Direction {
+	back := Point{-dir.Delta.X, -dir.Delta.Y}
+	for _, d := range Directions {
+		if d.Delta == back {
+			return d
+		}
+	}
+	return Direction{dir.Name, back, dir.Cost}
+}
+
+// ParseWire parses a comma separated path such as "R8,U5,L5,D3", reporting
+// it in errors as the n-th wire.
+func (p Parser) ParseWire(n int, line string) (Path, error) {
+	var path Path
+	column := 0
+	for i, token := range strings.Split(line, ",") {
+		if p.Strict || strings.TrimSpace(token) != "" {
+			move, err := p.ParseMove(token)
+			if err != nil {
+				pe := err.(*ParseError)
+				pe.Wire, pe.Token, pe.Column = n, i+1, column
+				return nil, pe
+			}
+			path = append(path, move)
+		}
+		column += len(token) + 1
+	}
+	return path, nil
+}
+
+// ParsePath parses a comma separated path such as "R8,U5,L5,D3".
+func (p Parser) ParsePath(line string) (Path, error) {
+	return p.ParseWire(0, line)
+}
+
+// ReadPaths reads one wire path per non-blank line.
+func (p Parser) ReadPaths(r io.Reader) ([]Path, error) {
+	var paths []Path
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		path, err := p.ParseWire(len(paths)+1, line)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, scanner.Err()
+}
+
+// ParseMove parses a single move such as "R8" with a lenient Parser.
+func ParseMove(token string) (Move, error) {
+	return Parser{}.ParseMove(token)
+}
+
+// ParsePath parses a comma separated path such as "R8,U5,L5,D3" with a lenient Parser.
+func ParsePath(line string) (Path, error) {
+	return Parser{}.ParsePath(line)
+}
+
+// ReadPaths reads one wire path per non-blank line with a lenient Parser.
+func ReadPaths(r io.Reader) ([]Path, error) {
+	return Parser{}.ReadPaths(r)
+}
+
+// Segment is a straight run of a wire. It covers the Length cells
+// Start+Delta, Start+2*Delta, ..., not Start itself, which belongs to the
+// segment before (or is the central port).
+type Segment struct {
+	Start  Point
+	Delta  Point
+	Length int
+	Steps  int // steps the wire has taken when it reaches Start
+	Cost   int // steps taken to enter each cell
+}
+
+// At is the t-th cell of the segment, for t from 1 to Length.
+func (s Segment) At(t int) Point {
+	return s.Start.Add(s.Delta.Scale(t))
+}
+
+// StepsAt is the steps the wire has taken when it reaches the t-th cell of the segment.
+func (s Segment) StepsAt(t int) int {
+	return s.Steps + t*s.Cost
+}
+
+// Find returns t such that s.At(t) == p, if the segment covers p.
+func (s Segment) Find(p Point) (int, bool) {
+	d := p.Sub(s.Start)
+	t := 0
+	switch {
+	case s.Delta.X != 0:
+		t = d.X / s.Delta.X
+	case s.Delta.Y != 0:
+		t = d.Y / s.Delta.Y
+	}
+	if t < 1 || t > s.Length || s.At(t) != p {
+		return 0, false
+	}
+	return t, true
+}
+
+// End is the last cell of the segment.
+func (s Segment) End() Point {
+	return s.At(s.Length)
+}
+
+// Segments turns a path into the segments it runs along.
+func (p Path) Segments() []Segment {
+	return p.SegmentsWithCosts(nil)
+}
+
+// SegmentsWithCosts is Segments with some step costs overridden.
+func (p Path) SegmentsWithCosts(costs Costs) []Segment {
+	segments := make([]Segment, 0, len(p))
+	pos, steps := Point{}, 0
+	for _, m := range p {
+		s := Segment{Start: pos, Delta: m.Direction.Delta, Length: m.Length, Steps: steps, Cost: costs.cost(m.Direction)}
+		if m.Length > 0 {
+			segments = append(segments, s)
+		}
+		pos, steps = s.End(), s.StepsAt(m.Length)
+	}
+	return segments
+}
+
+// Cells walks the wire one cell at a time, yielding each cell it enters and
+// the steps it has taken on entering it. A cell visited twice is yielded twice.
+func (p Path) Cells(costs Costs) iter.Seq2[Point, int] {
+	return func(yield func(Point, int) bool) {
+		for _, s := range p.SegmentsWithCosts(costs) {
+			for t := 1; t <= s.Length; t++ {
+				if !yield(s.At(t), s.StepsAt(t)) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package wire
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var pathSeeds = []string{
+	"R8,U5,L5,D3",
+	"U7,R6,D4,L4",
+	"R75,D30,R83,U83,L12,D49,R71,U7,L72",
+	"U62,R66,U55,R34,D71,R55,D58,R83",
+	"R98,U47,R26,D63,R33,U87,L62,D20,R33,U53,R51",
+	"U98,R91,D20,R16,D67,R40,U7,R15,U6,R7",
+	"r8,u5,l5,d3",
+	"ul3,Dr2",
+	" R8 , U 5,\tL5 ",
+	"R8,,U5,",
+	"",
+	" ",
+	"R0,L-3",
+	"R-9223372036854775808",
+	"X5,R",
+	"R8,U5x",
+}
+
+// checkParseError checks err is a *ParseError for the token-th move of the n-th
+// wire, at column, wrapping the error parsing that move alone gives.
+func checkParseError(t *testing.T, p Parser, err error, n, token, column int, text string) {
+	t.Helper()
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("error %v is a %T, want a *ParseError", err, err)
+	}
+	if pe.Wire != n || pe.Token != token || pe.Column != column || pe.Text != text {
+		t.Fatalf("error at wire %d, move %d, column %d, text %q, want wire %d, move %d, column %d, text %q",
+			pe.Wire, pe.Token, pe.Column, pe.Text, n, token, column, text)
+	}
+	if _, alone := p.ParseMove(text); alone == nil || !errors.Is(err, alone.(*ParseError).Err) {
+		t.Fatalf("error %v, but the move on its own gives %v", err, alone)
+	}
+	for _, sentinel := range []error{ErrEmpty, ErrDirection, ErrLength, ErrNegative} {
+		if errors.Is(err, sentinel) {
+			return
+		}
+	}
+	t.Fatalf("error %v wraps none of the package's errors", err)
+}
+
+func FuzzParseMove(f *testing.F) {
+	for _, seed := range pathSeeds {
+		for _, token := range strings.Split(seed, ",") {
+			f.Add(token, false)
+			f.Add(token, true)
+		}
+	}
+	f.Fuzz(func(t *testing.T, token string, strict bool) {
+		p := Parser{Strict: strict}
+		move, err := p.ParseMove(token)
+		if err != nil {
+			checkParseError(t, p, err, 0, 0, 0, token)
+			return
+		}
+		if move.Length < 0 || (strict && move.Length == 0) {
+			t.Fatalf("ParseMove(%q) = %v, the length should be positive", token, move)
+		}
+		again, err := p.ParseMove(move.String())
+		if err != nil || again != move {
+			t.Fatalf("ParseMove(%q) = %v, which parses back as %v, %v", token, move, again, err)
+		}
+	})
+}
+
+func FuzzParseWire(f *testing.F) {
+	for _, seed := range pathSeeds {
+		f.Add(seed, false)
+		f.Add(seed, true)
+	}
+	f.Fuzz(func(t *testing.T, line string, strict bool) {
+		p := Parser{Strict: strict}
+		path, err := p.ParseWire(2, line)
+		if err != nil {
+			// The error is at the first token that doesn't parse on its own.
+			column := 0
+			for i, token := range strings.Split(line, ",") {
+				if strict || strings.TrimSpace(token) != "" {
+					if _, tokenErr := p.ParseMove(token); tokenErr != nil {
+						checkParseError(t, p, err, 2, i+1, column, token)
+						return
+					}
+				}
+				column += len(token) + 1
+			}
+			t.Fatalf("ParseWire(%q) failed with %v, but every move parses", line, err)
+		}
+		again, err := p.ParseWire(2, path.String())
+		if err != nil || again.String() != path.String() || len(again) != len(path) {
+			t.Fatalf("ParseWire(%q) = %v, which parses back as %v, %v", line, path, again, err)
+		}
+		for i := range path {
+			if again[i] != path[i] {
+				t.Fatalf("ParseWire(%q) move %d = %v, which parses back as %v", line, i+1, path[i], again[i])
+			}
+		}
+	})
+}
+
+func TestCells(t *testing.T) {
+	path, err := Parser{}.ParsePath("UR2,R1,R0,DL3,u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type cell struct {
+		p     Point
+		steps int
+	}
+	for _, test := range []struct {
+		costs Costs
+		want  []cell
+	}{
+		{nil, []cell{{Point{1, 1}, 1}, {Point{2, 2}, 2}, {Point{3, 2}, 3}, {Point{2, 1}, 4}, {Point{1, 0}, 5}, {Point{0, -1}, 6}, {Point{0, 0}, 7}}},
+		{Costs{"UR": 2, "DL": 3}, []cell{{Point{1, 1}, 2}, {Point{2, 2}, 4}, {Point{3, 2}, 5}, {Point{2, 1}, 8}, {Point{1, 0}, 11}, {Point{0, -1}, 14}, {Point{0, 0}, 15}}},
+	} {
+		var got []cell
+		for p, steps := range path.Cells(test.costs) {
+			got = append(got, cell{p, steps})
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Cells(%v) = %v, want %v", test.costs, got, test.want)
+		}
+	}
+
+	// Stopping early stops the walk.
+	n := 0
+	for range path.Cells(nil) {
+		if n++; n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("walked %d cells after stopping at 3", n)
+	}
+}
+
+func TestParseCosts(t *testing.T) {
+	costs, err := ParseCosts(" UL=2, UR=3")
+	if err != nil || !reflect.DeepEqual(costs, Costs{"UL": 2, "UR": 3}) {
+		t.Errorf("ParseCosts = %v, %v", costs, err)
+	}
+	if costs, err := ParseCosts(""); err != nil || len(costs) != 0 {
+		t.Errorf("ParseCosts(\"\") = %v, %v", costs, err)
+	}
+	for _, text := range []string{"X=1", "U", "U=0", "U=-1", "U=x", "UL=2,"} {
+		if _, err := ParseCosts(text); err == nil {
+			t.Errorf("ParseCosts(%q) should fail", text)
+		}
+	}
+}
+
+func TestParseWire(t *testing.T) {
+	for _, test := range []struct {
+		line   string
+		strict bool
+		want   string
+	}{
+		{"R8,U5,L5,D3", true, "R8,U5,L5,D3"},
+		{"r8,u5,l5,d3", true, "R8,U5,L5,D3"},
+		{"ul3,Dr2,uR1,DL4", true, "UL3,DR2,UR1,DL4"},
+		{" R8 , U 5,\tL5 ", true, "R8,U5,L5"},
+		{"R8,,U5,", false, "R8,U5"},
+		{"R0,L-3", false, "R0,R3"},
+		{"UL-2", false, "DR2"},
+		{"", false, ""},
+	} {
+		path, err := Parser{Strict: test.strict}.ParseWire(1, test.line)
+		if err != nil || path.String() != test.want {
+			t.Errorf("ParseWire(%q) = %v, %v, want %s", test.line, path, err, test.want)
+		}
+	}
+
+	for _, test := range []struct {
+		line   string
+		strict bool
+		token  int
+		column int
+		err    error
+	}{
+		{"R8,X5", false, 2, 3, ErrDirection},
+		{"R8, 5", false, 2, 3, ErrDirection},
+		{"R8,U5x", false, 2, 3, ErrLength},
+		{"R", false, 1, 0, ErrLength},
+		{"R8,UL", false, 2, 3, ErrLength},
+		{"R8, U ,L5", false, 2, 3, ErrLength},
+		{"R99999999999999999999", false, 1, 0, ErrLength},
+		{"U1,R-9223372036854775808", false, 2, 3, ErrLength},
+		{"U1,R-9223372036854775808", true, 2, 3, ErrNegative},
+		{"R8,,U5", true, 2, 3, ErrEmpty},
+		{"R8,U5,", true, 3, 6, ErrEmpty},
+		{"R8,L-3", true, 2, 3, ErrNegative},
+		{"R8,  L0", true, 2, 3, ErrNegative},
+		{"", true, 1, 0, ErrEmpty},
+	} {
+		_, err := Parser{Strict: test.strict}.ParseWire(2, test.line)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, test.err) {
+			t.Errorf("ParseWire(%q, strict %v) error = %v, want %v", test.line, test.strict, err, test.err)
+			continue
+		}
+		if pe.Wire != 2 || pe.Token != test.token || pe.Column != test.column {
+			t.Errorf("ParseWire(%q, strict %v) error at wire %d, move %d, column %d, want wire 2, move %d, column %d",
+				test.line, test.strict, pe.Wire, pe.Token, pe.Column, test.token, test.column)
+		}
+		if token := strings.Split(test.line, ",")[test.token-1]; pe.Text != token {
+			t.Errorf("ParseWire(%q, strict %v) error text %q, want %q", test.line, test.strict, pe.Text, token)
+		}
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	_, err := Parser{}.ParseWire(2, "R8,U5x")
+	if want := `wire 2, move 2 (column 4), "U5x": length is not a number`; err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+	_, err = Parser{}.ParseMove("X5")
+	if want := `"X5": unknown direction, want L|D|U|R|UL|UR|DL|DR`; err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+}
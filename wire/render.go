@@ -0,0 +1,199 @@
+package wire
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// cell is what a Canvas knows about one grid cell: the first wire to cover
+// it (-1 for the central port) and the character the puzzle draws it with.
+type cell struct {
+	wire int
+	mark byte
+}
+
+// Canvas is the cells a set of wires cover, drawn the way the puzzle does:
+// - and | along the wires (/ and \ along diagonals), + where a wire turns or
+// crosses itself, o at the central port and X where different wires cross.
+type Canvas struct {
+	cells    map[Point]cell
+	min, max Point
+}
+
+// NewCanvas draws paths on a new canvas.
+func NewCanvas(paths []Path) *Canvas {
+	c := &Canvas{cells: map[Point]cell{{}: {-1, 'o'}}}
+	for i, path := range paths {
+		segments := path.Segments()
+		for j, s := range segments {
+			for t := 1; t <= s.Length; t++ {
+				mark := s.mark()
+				if t == s.Length && j+1 < len(segments) && segments[j+1].Delta != s.Delta {
+					mark = '+'
+				}
+				c.draw(s.At(t), i, mark)
+			}
+		}
+	}
+	return c
+}
+
+// mark is the character the cells of s are drawn with.
+func (s Segment) mark() byte {
+	switch {
+	case s.Delta.X == 0:
+		return '|'
+	case s.Delta.Y == 0:
+		return '-'
+	case s.Delta.X*s.Delta.Y > 0:
+		return '/'
+	}
+	return '\\'
+}
+
+func (c *Canvas) draw(p Point, wire int, mark byte) {
+	old, ok := c.cells[p]
+	switch {
+	case !ok:
+		c.min = Point{min(c.min.X, p.X), min(c.min.Y, p.Y)}
+		c.max = Point{max(c.max.X, p.X), max(c.max.Y, p.Y)}
+		c.cells[p] = cell{wire, mark}
+	case old.mark == 'o' || old.mark == 'X':
+	case old.wire != wire:
+		c.cells[p] = cell{old.wire, 'X'}
+	case old.mark != mark:
+		c.cells[p] = cell{wire, '+'}
+	}
+}
+
+// View is the part of a canvas to draw: the cells from Min to Max, with each
+// character or block of pixels standing for Scale by Scale cells.
+type View struct {
+	Min, Max Point
+	Scale    int
+}
+
+// View is the whole canvas with a one cell border, as the puzzle draws it.
+func (c *Canvas) View() View {
+	return View{c.min.Sub(Point{1, 1}), c.max.Add(Point{1, 1}), 1}
+}
+
+// Fit scales v to at most width by height characters or blocks. A limit of 0 leaves that side unscaled.
+func (v View) Fit(width, height int) View {
+	v.Scale = 1
+	if width > 0 {
+		v.Scale = max(v.Scale, (v.Max.X-v.Min.X+width)/width)
+	}
+	if height > 0 {
+		v.Scale = max(v.Scale, (v.Max.Y-v.Min.Y+height)/height)
+	}
+	return v
+}
+
+func (v View) size() (int, int) {
+	scale := max(v.Scale, 1)
+	return (v.Max.X-v.Min.X)/scale + 1, (v.Max.Y-v.Min.Y)/scale + 1
+}
+
+// blocks is the most telling cell of each block of v, row by row from the
+// top (largest y). Blocks no wire covers have a zero mark.
+func (c *Canvas) blocks(v View) ([]cell, int, int) {
+	scale := max(v.Scale, 1)
+	width, height := v.size()
+	blocks := make([]cell, width*height)
+	for p, cl := range c.cells {
+		if p.X < v.Min.X || p.X > v.Max.X || p.Y < v.Min.Y || p.Y > v.Max.Y {
+			continue
+		}
+		i := (v.Max.Y-p.Y)/scale*width + (p.X-v.Min.X)/scale
+		if blocks[i].mark == 0 || priority(cl.mark) > priority(blocks[i].mark) {
+			blocks[i] = cl
+		}
+	}
+	return blocks, width, height
+}
+
+// priority is how much a mark should win over the others in a scaled down block.
+func priority(mark byte) int {
+	return strings.IndexByte("-|/\\+oX", mark)
+}
+
+// ASCII draws the cells of v, one line per row, top row first.
+func (c *Canvas) ASCII(v View) string {
+	blocks, width, _ := c.blocks(v)
+	var b strings.Builder
+	for i, cl := range blocks {
+		if cl.mark == 0 {
+			b.WriteByte('.')
+		} else {
+			b.WriteByte(cl.mark)
+		}
+		if (i+1)%width == 0 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// Mark highlights a cell of a PNG with a square of Color, Radius pixels out from the cell.
+type Mark struct {
+	Point  Point
+	Color  color.Color
+	Radius int
+}
+
+// WireColors are the colours wires are drawn in, reused when there are more wires than colours.
+var WireColors = []color.Color{
+	color.RGBA{0x30, 0x80, 0xe0, 0xff},
+	color.RGBA{0x40, 0xc0, 0x40, 0xff},
+	color.RGBA{0xe0, 0xa0, 0x20, 0xff},
+	color.RGBA{0xb0, 0x50, 0xd0, 0xff},
+	color.RGBA{0x20, 0xc0, 0xc0, 0xff},
+}
+
+var (
+	backgroundColor = color.RGBA{0x10, 0x10, 0x10, 0xff}
+	portColor       = color.RGBA{0xf0, 0xf0, 0xf0, 0xff}
+	crossingColor   = color.RGBA{0xe0, 0x30, 0x30, 0xff}
+)
+
+// WritePNG draws v as a PNG, each block zoom pixels square, then draws marks over it.
+func (c *Canvas) WritePNG(w io.Writer, v View, zoom int, marks ...Mark) error {
+	zoom = max(zoom, 1)
+	blocks, width, height := c.blocks(v)
+	img := image.NewRGBA(image.Rect(0, 0, width*zoom, height*zoom))
+	fill := func(x0, y0, x1, y1 int, col color.Color) {
+		for y := max(y0, 0); y < min(y1, height*zoom); y++ {
+			for x := max(x0, 0); x < min(x1, width*zoom); x++ {
+				img.Set(x, y, col)
+			}
+		}
+	}
+	for i, cl := range blocks {
+		col := color.Color(backgroundColor)
+		switch cl.mark {
+		case 0:
+		case 'o':
+			col = portColor
+		case 'X':
+			col = crossingColor
+		default:
+			col = WireColors[cl.wire%len(WireColors)]
+		}
+		x, y := i%width*zoom, i/width*zoom
+		fill(x, y, x+zoom, y+zoom, col)
+	}
+	scale := max(v.Scale, 1)
+	for _, m := range marks {
+		if m.Point.X < v.Min.X || m.Point.X > v.Max.X || m.Point.Y < v.Min.Y || m.Point.Y > v.Max.Y {
+			continue
+		}
+		x := (m.Point.X - v.Min.X) / scale * zoom
+		y := (v.Max.Y - m.Point.Y) / scale * zoom
+		fill(x-m.Radius, y-m.Radius, x+zoom+m.Radius, y+zoom+m.Radius, m.Color)
+	}
+	return png.Encode(w, img)
+}
@@ -0,0 +1,99 @@
+package wire
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// puzzleDrawing is the puzzle's drawing of its first example.
+const puzzleDrawing = `...........
+.+-----+...
+.|.....|...
+.|..+--X-+.
+.|..|..|.|.
+.|.-X--+.|.
+.|..|....|.
+.|.......|.
+.o-------+.
+...........
+`
+
+func TestASCII(t *testing.T) {
+	canvas := NewCanvas(mustParse(t, "R8,U5,L5,D3", "U7,R6,D4,L4"))
+	view := canvas.View()
+	if want := (View{Point{-1, -1}, Point{9, 8}, 1}); view != want {
+		t.Fatalf("View = %v, want %v", view, want)
+	}
+	for _, test := range []struct {
+		view View
+		want string
+	}{
+		{view, puzzleDrawing},
+		{view.Fit(4, 3), "+X+\n|X|\no-+\n"},
+		{view.Fit(0, 0), puzzleDrawing},
+		{View{Point{2, 2}, Point{7, 5}, 1}, ".+--X-\n.|..|.\n-X--+.\n.|....\n"},
+	} {
+		if got := canvas.ASCII(test.view); got != test.want {
+			t.Errorf("ASCII(%v) =\n%s\nwant\n%s", test.view, got, test.want)
+		}
+	}
+
+	diagonal := NewCanvas(mustParse(t, "UR2,DR2", "DL1"))
+	if got, want := diagonal.ASCII(diagonal.View()), "........\n....+...\n.../.\\..\n..o...\\.\n./......\n........\n"; got != want {
+		t.Errorf("ASCII of diagonals =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	canvas := NewCanvas(mustParse(t, "R8,U5,L5,D3", "U7,R6,D4,L4"))
+	// Which colour each block of the puzzle drawing is: the background, wire 1
+	// or 2, the central port or a crossing.
+	blocks := strings.Fields(`
+		...........
+		.2222222...
+		.2.....2...
+		.2..111X11.
+		.2..1..2.1.
+		.2.2X222.1.
+		.2..1....1.
+		.2.......1.
+		.o11111111.
+		...........`)
+	colors := map[byte]color.Color{
+		'.': backgroundColor,
+		'1': WireColors[0],
+		'2': WireColors[1],
+		'o': portColor,
+		'X': crossingColor,
+	}
+	markColor := color.RGBA{0xff, 0xff, 0x00, 0xff}
+
+	var buf bytes.Buffer
+	const zoom = 2
+	if err := canvas.WritePNG(&buf, canvas.View(), zoom, Mark{Point{3, 3}, markColor, 1}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 11*zoom, 10*zoom); got != want {
+		t.Fatalf("PNG bounds %v, want %v", got, want)
+	}
+	for y := 0; y < 10*zoom; y++ {
+		for x := 0; x < 11*zoom; x++ {
+			want := colors[blocks[y/zoom][x/zoom]]
+			// The mark covers the crossing at 3,3 and one pixel around it.
+			if x >= 7 && x <= 10 && y >= 9 && y <= 12 {
+				want = markColor
+			}
+			if got := color.RGBAModel.Convert(img.At(x, y)); got != want {
+				t.Fatalf("pixel %d,%d is %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package wire
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+// ErrNoCrossing is returned when a closest or fewest step crossing is asked of wires that never cross.
+var ErrNoCrossing = errors.New("the wires never cross")
+
+// Intersection is one crossing in a report.
+type Intersection struct {
+	X        int `json:"x"`
+	Y        int `json:"y"`
+	Distance int `json:"distance"`
+	StepsA   int `json:"steps_a"`
+	StepsB   int `json:"steps_b"`
+	Steps    int `json:"steps"`
+}
+
+// Report lists every crossing of two wires. Crossed is false, and Closest and
+// Fewest are nil, when the wires never cross.
+type Report struct {
+	Crossed       bool           `json:"crossed"`
+	Closest       *Intersection  `json:"closest,omitempty"`
+	Fewest        *Intersection  `json:"fewest,omitempty"`
+	Intersections []Intersection `json:"intersections"`
+}
+
+// NewReport reports on crossings, listed in the order given.
+func NewReport(cs []Crossing) *Report {
+	r := &Report{Crossed: len(cs) > 0, Intersections: make([]Intersection, len(cs))}
+	for i, c := range cs {
+		r.Intersections[i] = intersection(c)
+	}
+	if closest, ok := Closest(cs); ok {
+		i := intersection(closest)
+		r.Closest = &i
+	}
+	if fewest, ok := Fewest(cs); ok {
+		i := intersection(fewest)
+		r.Fewest = &i
+	}
+	return r
+}
+
+func intersection(c Crossing) Intersection {
+	return Intersection{c.Point.X, c.Point.Y, c.Point.Manhattan(), c.StepsA, c.StepsB, c.Steps()}
+}
+
+// columns are the report's columns, by their CSV and JSON names.
+var columns = map[string]func(Intersection) int{
+	"x":        func(i Intersection) int { return i.X },
+	"y":        func(i Intersection) int { return i.Y },
+	"distance": func(i Intersection) int { return i.Distance },
+	"steps_a":  func(i Intersection) int { return i.StepsA },
+	"steps_b":  func(i Intersection) int { return i.StepsB },
+	"steps":    func(i Intersection) int { return i.Steps },
+}
+
+// SortBy orders the intersections by column, smallest first unless descending.
+// Ties keep their order.
+func (r *Report) SortBy(column string, descending bool) error {
+	key, ok := columns[column]
+	if !ok {
+		return fmt.Errorf("unknown column %q, want x, y, distance, steps_a, steps_b or steps", column)
+	}
+	sort.SliceStable(r.Intersections, func(i, j int) bool {
+		if descending {
+			return key(r.Intersections[i]) > key(r.Intersections[j])
+		}
+		return key(r.Intersections[i]) < key(r.Intersections[j])
+	})
+	return nil
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes one row per intersection. Wires that never cross have only the header row.
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"x", "y", "distance", "steps_a", "steps_b", "steps"})
+	for _, i := range r.Intersections {
+		writer.Write([]string{
+			strconv.Itoa(i.X),
+			strconv.Itoa(i.Y),
+			strconv.Itoa(i.Distance),
+			strconv.Itoa(i.StepsA),
+			strconv.Itoa(i.StepsB),
+			strconv.Itoa(i.Steps),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteTable writes the report as an aligned text table, followed by the
+// closest and fewest step crossings or a line saying there are none.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "X\tY\tDistance\tSteps A\tSteps B\tSteps\t")
+	for _, i := range r.Intersections {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%d\t\n", i.X, i.Y, i.Distance, i.StepsA, i.StepsB, i.Steps)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if !r.Crossed {
+		_, err := fmt.Fprintln(w, "No intersection: "+ErrNoCrossing.Error())
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Closest: %d,%d at distance %d\nFewest steps: %d,%d at %d steps\n",
+		r.Closest.X, r.Closest.Y, r.Closest.Distance, r.Fewest.X, r.Fewest.Y, r.Fewest.Steps)
+	return err
+}
+
+// Write writes the report in format: "table", "csv" or "json".
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case "table":
+		return r.WriteTable(w)
+	case "csv":
+		return r.WriteCSV(w)
+	case "json":
+		return r.WriteJSON(w)
+	}
+	return fmt.Errorf("unknown format %q, want table, csv or json", format)
+}
@@ -0,0 +1,81 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestReportSortBy(t *testing.T) {
+	cs := []Crossing{
+		{Point: Point{3, 3}, StepsA: 20, StepsB: 20},
+		{Point: Point{6, 5}, StepsA: 15, StepsB: 15},
+		{Point: Point{-2, 4}, StepsA: 25, StepsB: 5},
+		{Point: Point{1, -1}, StepsA: 4, StepsB: 8},
+	}
+	for _, test := range []struct {
+		column     string
+		descending bool
+		want       []Point
+	}{
+		{"distance", false, []Point{{1, -1}, {3, 3}, {-2, 4}, {6, 5}}},
+		{"distance", true, []Point{{6, 5}, {3, 3}, {-2, 4}, {1, -1}}},
+		{"steps", false, []Point{{1, -1}, {6, 5}, {-2, 4}, {3, 3}}},
+		{"steps_a", true, []Point{{-2, 4}, {3, 3}, {6, 5}, {1, -1}}},
+		{"steps_b", false, []Point{{-2, 4}, {1, -1}, {6, 5}, {3, 3}}},
+		{"x", false, []Point{{-2, 4}, {1, -1}, {3, 3}, {6, 5}}},
+		{"y", true, []Point{{6, 5}, {-2, 4}, {3, 3}, {1, -1}}},
+	} {
+		r := NewReport(cs)
+		if err := r.SortBy(test.column, test.descending); err != nil {
+			t.Fatal(err)
+		}
+		var got []Point
+		for _, i := range r.Intersections {
+			got = append(got, Point{i.X, i.Y})
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SortBy(%s, %v) = %v, want %v", test.column, test.descending, got, test.want)
+		}
+		if *r.Closest != intersection(cs[3]) || *r.Fewest != intersection(cs[3]) {
+			t.Errorf("closest %v and fewest %v, want both %v", *r.Closest, *r.Fewest, cs[3].Point)
+		}
+	}
+
+	if err := NewReport(cs).SortBy("Steps", false); err == nil {
+		t.Errorf("SortBy accepted an unknown column")
+	}
+}
+
+func TestReportNoCrossing(t *testing.T) {
+	paths := mustParse(t, "R8,U5", "L3,D2")
+	r := NewReport(Intersect(paths[0].Segments(), paths[1].Segments()))
+	if r.Crossed || r.Closest != nil || r.Fewest != nil || len(r.Intersections) != 0 {
+		t.Fatalf("report of wires that never cross = %+v", r)
+	}
+	for _, test := range []struct {
+		format, want string
+	}{
+		{"table", "  X  Y  Distance  Steps A  Steps B  Steps\nNo intersection: the wires never cross\n"},
+		{"csv", "x,y,distance,steps_a,steps_b,steps\n"},
+		{"json", "{\n  \"crossed\": false,\n  \"intersections\": []\n}\n"},
+	} {
+		var buf bytes.Buffer
+		if err := r.Write(&buf, test.format); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.want {
+			t.Errorf("%s report =\n%s\nwant\n%s", test.format, buf.String(), test.want)
+		}
+	}
+
+	var decoded Report
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || !reflect.DeepEqual(&decoded, r) {
+		t.Errorf("JSON round trip = %+v, %v, want %+v", decoded, err, r)
+	}
+}
@@ -0,0 +1,141 @@
+package wire
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+)
+
+// ErrNoRoute is returned when a Router cannot reach its target.
+var ErrNoRoute = errors.New("no route to the target")
+
+// defaultMaxNodes is how many cells a Router searches when MaxNodes is unset.
+const defaultMaxNodes = 5000000
+
+// Router plans the path of a new wire out of the central port, moving only
+// left, right, up and down, with as few steps as it can.
+type Router struct {
+	// Penalty is the extra cost of entering a cell an existing wire covers.
+	// A negative Penalty forbids those cells, other than the target itself.
+	Penalty int
+	// MaxNodes is how many cells to search before giving up, 0 for a default.
+	MaxNodes int
+}
+
+// routeState is a cell and the direction the route entered it in (-1 at the central port).
+type routeState struct {
+	cell Point
+	dir  int
+}
+
+// routeCost is how good a partial route is: its cost first, then how few turns it takes.
+type routeCost struct {
+	cost, turns int
+}
+
+func (c routeCost) less(d routeCost) bool {
+	return c.cost < d.cost || (c.cost == d.cost && c.turns < d.turns)
+}
+
+type routeItem struct {
+	state routeState
+	g, f  routeCost
+}
+
+// routeQueue is a priority queue of routeItems, best estimate first.
+type routeQueue []routeItem
+
+func (q routeQueue) Len() int           { return len(q) }
+func (q routeQueue) Less(i, j int) bool { return q[i].f.less(q[j].f) }
+func (q routeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *routeQueue) Push(x any)        { *q = append(*q, x.(routeItem)) }
+func (q *routeQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// routeDirections are the directions a route can take.
+var routeDirections = []Direction{Directions["R"], Directions["U"], Directions["L"], Directions["D"]}
+
+// Route finds the cheapest path from the central port to target around the
+// existing wires, using A* with the Manhattan distance as its estimate. Routes
+// stay within a cell of the box around the wires and target: going further
+// out can never be shorter.
+func (r Router) Route(existing []Path, target Point) (Path, error) {
+	if target == (Point{}) {
+		return nil, fmt.Errorf("the target is the central port")
+	}
+	maxNodes := r.MaxNodes
+	if maxNodes <= 0 {
+		maxNodes = defaultMaxNodes
+	}
+	covered := make(map[Point]struct{})
+	lo, hi := Point{min(target.X, 0), min(target.Y, 0)}, Point{max(target.X, 0), max(target.Y, 0)}
+	for _, path := range existing {
+		for cell := range path.Cells(nil) {
+			covered[cell] = struct{}{}
+			lo = Point{min(lo.X, cell.X), min(lo.Y, cell.Y)}
+			hi = Point{max(hi.X, cell.X), max(hi.Y, cell.Y)}
+		}
+	}
+	lo, hi = lo.Sub(Point{1, 1}), hi.Add(Point{1, 1})
+
+	start := routeState{Point{}, -1}
+	best := map[routeState]routeCost{start: {}}
+	from := make(map[routeState]routeState)
+	queue := &routeQueue{{start, routeCost{}, routeCost{target.Manhattan(), 0}}}
+	for searched := 0; queue.Len() > 0; searched++ {
+		if searched == maxNodes {
+			return nil, fmt.Errorf("%w within %d cells searched", ErrNoRoute, maxNodes)
+		}
+		item := heap.Pop(queue).(routeItem)
+		if g := best[item.state]; g.less(item.g) {
+			continue
+		}
+		if item.state.cell == target {
+			return routePath(from, item.state), nil
+		}
+		for dir, d := range routeDirections {
+			next := routeState{item.state.cell.Add(d.Delta), dir}
+			if next.cell.X < lo.X || next.cell.X > hi.X || next.cell.Y < lo.Y || next.cell.Y > hi.Y {
+				continue
+			}
+			g := routeCost{item.g.cost + 1, item.g.turns}
+			if dir != item.state.dir && item.state.dir >= 0 {
+				g.turns++
+			}
+			if _, ok := covered[next.cell]; ok {
+				if r.Penalty < 0 && next.cell != target {
+					continue
+				}
+				g.cost += max(r.Penalty, 0)
+			}
+			if old, ok := best[next]; ok && !g.less(old) {
+				continue
+			}
+			best[next], from[next] = g, item.state
+			heap.Push(queue, routeItem{next, g, routeCost{g.cost + target.Sub(next.cell).Manhattan(), g.turns}})
+		}
+	}
+	return nil, ErrNoRoute
+}
+
+// routePath follows the route back from end to the central port, joining
+// runs of cells in one direction into single moves.
+func routePath(from map[routeState]routeState, end routeState) Path {
+	var path Path
+	for s := end; s.dir >= 0; s = from[s] {
+		dir := routeDirections[s.dir]
+		if len(path) > 0 && path[len(path)-1].Direction == dir {
+			path[len(path)-1].Length++
+		} else {
+			path = append(path, Move{dir, 1})
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
@@ -0,0 +1,78 @@
+package wire
+
+import (
+	"errors"
+	"testing"
+)
+
+// checkRoute checks route runs from the central port to target in steps steps
+// and moves moves, entering no cell of existing other than the target when avoid is set.
+func checkRoute(t *testing.T, route Path, existing []Path, target Point, steps, moves int, avoid bool) {
+	t.Helper()
+	covered := make(map[Point]bool)
+	for _, path := range existing {
+		for cell := range path.Cells(nil) {
+			covered[cell] = true
+		}
+	}
+	var end Point
+	n := 0
+	for cell, s := range route.Cells(nil) {
+		if avoid && covered[cell] && cell != target {
+			t.Errorf("route %v enters %v, which an existing wire covers", route, cell)
+		}
+		end, n = cell, s
+	}
+	if end != target || n != steps || len(route) != moves {
+		t.Errorf("route %v ends at %v after %d steps and %d moves, want %v after %d steps and %d moves",
+			route, end, n, len(route), target, steps, moves)
+	}
+}
+
+func TestRoute(t *testing.T) {
+	// A wall at x = 1 from y = -3 to 3.
+	wall := mustParse(t, "R1,U3,D6")
+	for _, test := range []struct {
+		name     string
+		existing []Path
+		penalty  int
+		target   Point
+		steps    int
+		moves    int
+	}{
+		{"open grid", nil, -1, Point{3, -2}, 5, 2},
+		{"around the wall", wall, -1, Point{3, 0}, 11, 3},
+		{"through the wall", wall, 0, Point{3, 0}, 3, 1},
+		{"through the cheap wall", wall, 2, Point{3, 0}, 3, 1},
+		{"around the dear wall", wall, 10, Point{3, 0}, 11, 3},
+		{"onto the wall", wall, -1, Point{1, 2}, 3, 2},
+	} {
+		route, err := Router{Penalty: test.penalty}.Route(test.existing, test.target)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		checkRoute(t, route, test.existing, test.target, test.steps, test.moves, test.penalty < 0)
+	}
+}
+
+func TestNoRoute(t *testing.T) {
+	// A wire boxing in the central port.
+	box := mustParse(t, "U1,R1,D2,L2,U2,R1")
+	if _, err := (Router{Penalty: -1}).Route(box, Point{5, 5}); !errors.Is(err, ErrNoRoute) {
+		t.Errorf("route out of a box = %v, want %v", err, ErrNoRoute)
+	}
+	if route, err := (Router{Penalty: 1}).Route(box, Point{5, 5}); err != nil {
+		t.Errorf("route out of a box with a penalty = %v", err)
+	} else {
+		checkRoute(t, route, box, Point{5, 5}, 10, 2, false)
+	}
+
+	_, err := Router{MaxNodes: 3}.Route(nil, Point{50, 50})
+	if !errors.Is(err, ErrNoRoute) || err.Error() != "no route to the target within 3 cells searched" {
+		t.Errorf("route with 3 nodes = %v", err)
+	}
+	if _, err := (Router{}).Route(nil, Point{}); err == nil {
+		t.Errorf("routed to the central port")
+	}
+}